@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// ErrAADNotSupported error
+var ErrAADNotSupported = errors.New("associated data not supported")
+
+// AEAD for authenticated encryption with associated data
+type AEAD interface {
+	// Seal returns encrypted plaintext, aad is authenticated but not encrypted
+	Seal(plaintext, aad []byte) ([]byte, error)
+	// Open returns decrypted ciphertext, aad must be the same as the one given to Seal
+	Open(ciphertext, aad []byte) ([]byte, error)
+}
+
+// Encoding for sealed ciphertext
+type Encoding interface {
+	EncodeToString(src []byte) string
+	DecodeString(s string) ([]byte, error)
+}
+
+var (
+	// RawEncoding leaves ciphertext bytes as they are
+	RawEncoding Encoding = rawEncoding{}
+	// HexEncoding encodes ciphertext as hex, same as Cipher.Hex
+	HexEncoding Encoding = hexEncoding{}
+	// Base64Encoding encodes ciphertext as base64 URL, same as Cipher.Base64
+	Base64Encoding Encoding = base64.URLEncoding
+)
+
+// rawEncoding implements Encoding without any encoding
+type rawEncoding struct{}
+
+// hexEncoding implements Encoding using hex
+type hexEncoding struct{}
+
+// EncodedAEAD will encode sealed ciphertext using Encoding
+type EncodedAEAD struct {
+	AEAD
+	Encoding
+}
+
+// AEADCrypter adapts AEAD to Crypter so it can be used with Cipher, aad is always empty
+type AEADCrypter struct {
+	AEAD
+}
+
+// crypterAEAD adapts Crypter to AEAD
+type crypterAEAD struct {
+	crypter Crypter
+}
+
+// CrypterAEAD will adapt crypter to AEAD, crypter does not support aad
+func CrypterAEAD(crypter Crypter) AEAD {
+	if aead, ok := crypter.(AEAD); ok {
+		return aead
+	}
+
+	return &crypterAEAD{
+		crypter: crypter,
+	}
+}
+
+// SealToString will seal plaintext and encode it
+func (encoded *EncodedAEAD) SealToString(plaintext, aad []byte) (string, error) {
+	sealed, err := encoded.AEAD.Seal(plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+
+	return encoded.encoding().EncodeToString(sealed), nil
+}
+
+// OpenString will decode ciphertext and open it
+func (encoded *EncodedAEAD) OpenString(ciphertext string, aad []byte) ([]byte, error) {
+	sealed, err := encoded.encoding().DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoded.AEAD.Open(sealed, aad)
+}
+
+// encoding is helper function to fallback to RawEncoding
+func (encoded *EncodedAEAD) encoding() Encoding {
+	if encoded.Encoding == nil {
+		return RawEncoding
+	}
+
+	return encoded.Encoding
+}
+
+// Encrypt implements Crypter.Encrypt
+func (crypter *AEADCrypter) Encrypt(payload []byte) (string, string, string, error) {
+	if crypter.AEAD == nil {
+		return "", "", "", ErrMissingCrypter
+	}
+
+	encrypted, err := crypter.AEAD.Seal(payload, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt implements Crypter.Decrypt
+func (crypter *AEADCrypter) Decrypt(encrypted string) (string, error) {
+	if crypter.AEAD == nil {
+		return "", ErrMissingCrypter
+	}
+
+	decrypted, err := crypter.AEAD.Open([]byte(encrypted), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// Seal implements AEAD.Seal
+func (aead *crypterAEAD) Seal(plaintext, aad []byte) ([]byte, error) {
+	if len(aad) > 0 {
+		return nil, ErrAADNotSupported
+	}
+
+	encrypted, _, _, err := aead.crypter.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(encrypted), nil
+}
+
+// Open implements AEAD.Open
+func (aead *crypterAEAD) Open(ciphertext, aad []byte) ([]byte, error) {
+	if len(aad) > 0 {
+		return nil, ErrAADNotSupported
+	}
+
+	decrypted, err := aead.crypter.Decrypt(string(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(decrypted), nil
+}
+
+// sealAEAD is helper function to seal plaintext with random nonce prepended to it
+func sealAEAD(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// openAEAD is helper function to open ciphertext sealed with sealAEAD
+func openAEAD(aead cipher.AEAD, ciphertext, aad []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+
+	if len(ciphertext) < nonceSize {
+		return nil, ErrEncryptedTooShort
+	}
+
+	nonce, encrypted := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	return aead.Open(nil, nonce, encrypted, aad)
+}
+
+// EncodeToString implements Encoding.EncodeToString
+func (rawEncoding) EncodeToString(src []byte) string {
+	return string(src)
+}
+
+// DecodeString implements Encoding.DecodeString
+func (rawEncoding) DecodeString(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+// EncodeToString implements Encoding.EncodeToString
+func (hexEncoding) EncodeToString(src []byte) string {
+	return hex.EncodeToString(src)
+}
+
+// DecodeString implements Encoding.DecodeString
+func (hexEncoding) DecodeString(s string) ([]byte, error) {
+	return hex.DecodeString(s)
+}
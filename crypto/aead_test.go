@@ -0,0 +1,106 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestGCMSealOpen(t *testing.T) {
+	aead := &crypto.GCM{
+		Secret: secret,
+	}
+
+	aad := []byte("user-123")
+
+	sealed, err := aead.Seal([]byte(message), aad)
+	assert.NoError(t, err)
+
+	opened, err := aead.Open(sealed, aad)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, string(opened))
+
+	_, err = aead.Open(sealed, []byte("user-456"))
+	assert.Error(t, err)
+
+	_, err = aead.Open(sealed, nil)
+	assert.Error(t, err)
+}
+
+func TestEncodedAEAD(t *testing.T) {
+	encodings := []crypto.Encoding{
+		nil,
+		crypto.RawEncoding,
+		crypto.HexEncoding,
+		crypto.Base64Encoding,
+	}
+
+	for _, encoding := range encodings {
+		aead := &crypto.EncodedAEAD{
+			AEAD:     &crypto.GCM{Secret: secret},
+			Encoding: encoding,
+		}
+
+		sealed, err := aead.SealToString([]byte(message), []byte("aad"))
+		assert.NoError(t, err)
+
+		opened, err := aead.OpenString(sealed, []byte("aad"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, message, string(opened))
+	}
+}
+
+func TestEncodedAEADInvalidEncoding(t *testing.T) {
+	aead := &crypto.EncodedAEAD{
+		AEAD:     &crypto.GCM{Secret: secret},
+		Encoding: crypto.HexEncoding,
+	}
+
+	_, err := aead.OpenString("not hex", nil)
+
+	assert.Error(t, err)
+}
+
+func TestAEADCrypter(t *testing.T) {
+	cipher := &crypto.Cipher{
+		Crypter: &crypto.AEADCrypter{
+			AEAD: &crypto.GCM{Secret: secret},
+		},
+	}
+
+	err := cipher.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	err = cipher.Decrypt(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, cipher.Decrypted)
+
+	err = cipher.Decrypt("invalid")
+
+	assert.Error(t, err)
+}
+
+func TestCrypterAEAD(t *testing.T) {
+	aead := crypto.CrypterAEAD(&crypto.CBC{
+		Secret: secret,
+		IV:     iv,
+	})
+
+	sealed, err := aead.Seal([]byte(message), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cbcEncrypted, string(sealed))
+
+	opened, err := aead.Open(sealed, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, string(opened))
+
+	_, err = aead.Seal([]byte(message), []byte("aad"))
+	assert.Equal(t, crypto.ErrAADNotSupported, err)
+}
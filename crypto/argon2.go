@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -13,6 +14,13 @@ import (
 
 // Credits to: https://www.alexedwards.net/blog/how-to-hash-and-verify-passwords-with-argon2-in-go
 
+const (
+	// argon2ID is PHC identifier for Argon2
+	argon2ID = "argon2id"
+	// argon2MaxMemory is max memory (KiB) of decoded hashes, 4 GiB
+	argon2MaxMemory = 4 << 20
+)
+
 // Argon2 hashing algorithm
 type Argon2 struct {
 	Plain   string
@@ -61,29 +69,98 @@ func (argon *Argon2) Hash() error {
 
 // Validate argon.Plain against argon.Hashed
 func (argon *Argon2) Validate() bool {
-	existing, err := decodeArgonHash(argon.Hashed)
+	valid, err := argon.Verify(argon.Plain, argon.Hashed)
 	if err != nil {
 		return false
 	}
 
-	dk := argon2.IDKey([]byte(argon.Plain), existing.Salt, existing.Time, existing.Memory, existing.Threads, existing.KeyLen)
+	return valid
+}
+
+// ID implements PasswordHasher.ID
+func (argon *Argon2) ID() string {
+	return argon2ID
+}
+
+// Encode implements PasswordHasher.Encode, argon itself is not modified
+func (argon *Argon2) Encode(plain string) (string, error) {
+	if plain == "" {
+		return "", ErrMissingPlain
+	}
+
+	salt, err := GenerateSalt(argon.SaltLen)
+	if err != nil {
+		return "", err
+	}
+
+	dk := argon2.IDKey([]byte(plain), salt, argon.Time, argon.Memory, argon.Threads, argon.KeyLen)
+
+	return fmt.Sprintf("$%s$v=%d$%s$%s$%s",
+		argon2ID, argon2.Version, argon.Params(),
+		phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(dk)), nil
+}
+
+// Verify implements PasswordHasher.Verify, both PHC and legacy encoded hashes are accepted
+func (argon *Argon2) Verify(plain, encoded string) (bool, error) {
+	existing, err := decodeArgonHash(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	dk := argon2.IDKey([]byte(plain), existing.Salt, existing.Time, existing.Memory, existing.Threads, existing.KeyLen)
+
+	return subtle.ConstantTimeCompare(existing.DK, dk) == 1, nil
+}
+
+// Params implements KDF.Params
+func (argon *Argon2) Params() string {
+	return fmt.Sprintf("m=%d,t=%d,p=%d", argon.Memory, argon.Time, argon.Threads)
+}
+
+// Key implements KDF.Key
+func (argon *Argon2) Key(secret, salt []byte, keyLen int) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	if keyLen <= 0 || int64(keyLen) > math.MaxUint32 {
+		return nil, ErrInvalidKeyLen
+	}
 
-	if subtle.ConstantTimeCompare(existing.DK, dk) == 1 {
+	return argon2.IDKey(secret, salt, argon.Time, argon.Memory, argon.Threads, uint32(keyLen)), nil
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+// Legacy encoded hashes and hashes with params different from argon need rehash
+func (argon *Argon2) NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, "$") {
 		return true
 	}
 
-	return false
+	existing, err := decodeArgonHash(encoded)
+	if err != nil {
+		return true
+	}
+
+	return existing.Memory != argon.Memory ||
+		existing.Time != argon.Time ||
+		existing.Threads != argon.Threads ||
+		existing.SaltLen != argon.SaltLen ||
+		existing.KeyLen != argon.KeyLen
 }
 
-// decodeArgonHash
+// decodeArgonHash will decode PHC ($argon2id$v=19$m=65536,t=3,p=2$salt$dk)
+// or legacy (19$65536$3$2$salt$dk) encoded hash
 func decodeArgonHash(encodedHash string) (*Argon2, error) {
+	if strings.HasPrefix(encodedHash, "$") {
+		return decodeArgonPHC(encodedHash)
+	}
+
 	vals := strings.Split(encodedHash, "$")
 	if len(vals) != 6 {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 
-	argon := &Argon2{}
-
 	version, err := strconv.Atoi(vals[0])
 	if err != nil {
 		return nil, err
@@ -96,19 +173,21 @@ func decodeArgonHash(encodedHash string) (*Argon2, error) {
 	if err != nil {
 		return nil, err
 	}
-	argon.Memory = uint32(memory)
 
 	time, err := strconv.Atoi(vals[2])
 	if err != nil {
 		return nil, err
 	}
-	argon.Time = uint32(time)
 
 	threads, err := strconv.Atoi(vals[3])
 	if err != nil {
 		return nil, err
 	}
-	argon.Threads = uint8(threads)
+
+	argon, err := newArgonParams(memory, time, threads)
+	if err != nil {
+		return nil, err
+	}
 
 	argon.Salt, err = hex.DecodeString(vals[4])
 	if err != nil {
@@ -122,5 +201,72 @@ func decodeArgonHash(encodedHash string) (*Argon2, error) {
 	}
 	argon.KeyLen = uint32(len(argon.DK))
 
+	if len(argon.Salt) == 0 || len(argon.DK) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	return argon, nil
+}
+
+// decodeArgonPHC is helper function to decode argon2id PHC string
+func decodeArgonPHC(encodedHash string) (*Argon2, error) {
+	phc, err := parsePHC(encodedHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if phc.ID != argon2ID || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, ErrInvalidHash
+	}
+	if phc.Version != argon2.Version {
+		return nil, errors.New("incompatible argon2 version")
+	}
+
+	argon, err := argonFromParams(phc)
+	if err != nil {
+		return nil, err
+	}
+
+	argon.Salt = phc.Salt
+	argon.SaltLen = len(phc.Salt)
+	argon.DK = phc.Hash
+	argon.KeyLen = uint32(len(phc.Hash))
+
 	return argon, nil
 }
+
+// argonFromParams is helper function to create Argon2 from m, t and p PHC params
+func argonFromParams(phc *phcHash) (*Argon2, error) {
+	memory, err := phc.param("m")
+	if err != nil {
+		return nil, err
+	}
+
+	time, err := phc.param("t")
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := phc.param("p")
+	if err != nil {
+		return nil, err
+	}
+
+	return newArgonParams(memory, time, threads)
+}
+
+// newArgonParams is helper function to create Argon2 from decoded m, t and p,
+// out of range values would overflow or make argon2.IDKey panic, memory is limited to 4 GiB
+func newArgonParams(memory, time, threads int) (*Argon2, error) {
+	if memory <= 0 || int64(memory) > argon2MaxMemory ||
+		time <= 0 || int64(time) > math.MaxUint32 ||
+		threads <= 0 || threads > math.MaxUint8 {
+		return nil, ErrInvalidHash
+	}
+
+	return &Argon2{
+		Memory:  uint32(memory),
+		Time:    uint32(time),
+		Threads: uint8(threads),
+	}, nil
+}
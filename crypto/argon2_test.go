@@ -1,6 +1,7 @@
 package crypto_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,3 +62,79 @@ func TestArgon2HashPlainMissing(t *testing.T) {
 
 	assert.Equal(t, crypto.ErrMissingPlain, err)
 }
+
+func TestArgon2Encode(t *testing.T) {
+	argon := crypto.NewArgon2()
+
+	encoded, err := argon.Encode(plainArgon)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=3,p=2$"))
+	assert.Empty(t, argon.Hashed)
+
+	valid, err := argon.Verify(plainArgon, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	argon.Plain = plainArgon
+	argon.Hashed = encoded
+
+	assert.True(t, argon.Validate())
+}
+
+func TestArgon2VerifyLegacy(t *testing.T) {
+	argon := crypto.NewArgon2()
+
+	valid, err := argon.Verify(plainArgon, hashedArgon)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = argon.Verify("invalid", hashedArgon)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestArgon2NeedsRehash(t *testing.T) {
+	argon := crypto.NewArgon2()
+
+	encoded, err := argon.Encode(plainArgon)
+	assert.NoError(t, err)
+
+	assert.False(t, argon.NeedsRehash(encoded))
+	assert.True(t, argon.NeedsRehash(hashedArgon))
+
+	argon.Memory = 128 * 1024
+
+	assert.True(t, argon.NeedsRehash(encoded))
+}
+
+func TestArgon2VerifyInvalidParams(t *testing.T) {
+	argon := crypto.NewArgon2()
+
+	hashes := []string{
+		"19$65536$0$2$00$00",
+		"19$65536$3$0$00$00",
+		"19$65536$3$256$00$00",
+		"19$0$3$2$00$00",
+		"19$-1$3$2$00$00",
+		"19$65536$4294967296$2$00$00",
+		"19$4294967295$3$2$00$00",
+		"19$65536$3$2$00$",
+		"19$65536$3$2$$00",
+		"$argon2id$v=19$m=65536,t=0,p=2$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=3,p=0$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=3,p=256$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=0,t=3,p=2$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=4294967295,t=3,p=2$c2FsdA$aGFzaA",
+	}
+
+	for _, hashed := range hashes {
+		valid, err := argon.Verify(plainArgon, hashed)
+
+		assert.Equal(t, crypto.ErrInvalidHash, err, hashed)
+		assert.False(t, valid, hashed)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// bcryptID is identifier of hashes produced by bcrypt, $2b$ and $2y$ are accepted as well
+const bcryptID = "2a"
+
 // BCrypt hashing algorithm
 type BCrypt struct {
 	Plain  string
@@ -38,3 +41,47 @@ func (bCrypt *BCrypt) Hash() error {
 func (bCrypt *BCrypt) Validate() bool {
 	return bcrypt.CompareHashAndPassword([]byte(bCrypt.Hashed), []byte(bCrypt.Plain)) == nil
 }
+
+// ID implements PasswordHasher.ID
+func (bCrypt *BCrypt) ID() string {
+	return bcryptID
+}
+
+// Encode implements PasswordHasher.Encode, bCrypt itself is not modified
+func (bCrypt *BCrypt) Encode(plain string) (string, error) {
+	if plain == "" {
+		return "", ErrMissingPlain
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bCrypt.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+// Verify implements PasswordHasher.Verify
+func (bCrypt *BCrypt) Verify(plain, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain))
+
+	switch err {
+	case nil:
+		return true, nil
+	case bcrypt.ErrMismatchedHashAndPassword:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+// Hashes with cost different from bCrypt.Cost need rehash
+func (bCrypt *BCrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost != bCrypt.Cost
+}
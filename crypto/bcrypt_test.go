@@ -1,6 +1,7 @@
 package crypto_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/semirm-dev/godev/crypto"
@@ -54,3 +55,33 @@ func TestBCryptHashPlainMissing(t *testing.T) {
 
 	assert.Equal(t, crypto.ErrMissingPlain, err)
 }
+
+func TestBCryptEncode(t *testing.T) {
+	bCrypt := crypto.NewBCrypt()
+
+	encoded, err := bCrypt.Encode(plainBCrypt)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$2a$10$"))
+
+	valid, err := bCrypt.Verify(plainBCrypt, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = bCrypt.Verify("invalid", encoded)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestBCryptNeedsRehash(t *testing.T) {
+	bCrypt := crypto.NewBCrypt()
+
+	assert.False(t, bCrypt.NeedsRehash(hashedBCrypt))
+
+	bCrypt.Cost = 12
+
+	assert.True(t, bCrypt.NeedsRehash(hashedBCrypt))
+	assert.True(t, bCrypt.NeedsRehash("invalid"))
+}
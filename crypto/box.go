@@ -0,0 +1,152 @@
+package crypto
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// BoxSuite is AEAD used by SealedBox
+type BoxSuite byte
+
+const (
+	// BoxAESGCM uses AES-256 GCM
+	BoxAESGCM BoxSuite = iota + 1
+	// BoxChaCha20 uses ChaCha20-Poly1305
+	BoxChaCha20
+)
+
+// boxHeaderSize: suite (1 byte) | ephemeral public key (32 bytes)
+const boxHeaderSize = 1 + curve25519.PointSize
+
+// SealedBox crypter, hybrid public key encryption using X25519 key agreement, HKDF-SHA256 and AEAD
+// Anyone with recipient PublicKey can encrypt, only PrivateKey holder can decrypt
+// Format: suite (1 byte) | ephemeral public key (32 bytes) | nonce | encrypted
+type SealedBox struct {
+	PublicKey  X25519PublicKey
+	PrivateKey X25519PrivateKey
+	// Suite used for encryption, BoxAESGCM if not set
+	Suite BoxSuite
+}
+
+// Encrypt payload for PublicKey
+func (box *SealedBox) Encrypt(payload []byte) (string, string, string, error) {
+	encrypted, err := box.Seal(payload, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt input encrypted for PublicKey of PrivateKey
+func (box *SealedBox) Decrypt(encrypted string) (string, error) {
+	decrypted, err := box.Open([]byte(encrypted), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// Seal implements AEAD.Seal using ephemeral key pair
+func (box *SealedBox) Seal(plaintext, aad []byte) ([]byte, error) {
+	if len(box.PublicKey) != curve25519.PointSize {
+		return nil, ErrInvalidKey
+	}
+
+	suite := box.Suite
+	if suite == 0 {
+		suite = BoxAESGCM
+	}
+
+	ephemeral, err := GenerateX25519Key()
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralPub, err := ephemeral.Public()
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := curve25519.X25519(ephemeral, box.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := boxAEAD(suite, shared, ephemeralPub, box.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, boxHeaderSize)
+	header = append(header, byte(suite))
+	header = append(header, ephemeralPub...)
+
+	sealed, err := sealAEAD(aead, plaintext, append(header, aad...))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, sealed...), nil
+}
+
+// Open implements AEAD.Open using PrivateKey
+func (box *SealedBox) Open(ciphertext, aad []byte) ([]byte, error) {
+	if len(box.PrivateKey) != curve25519.ScalarSize {
+		return nil, ErrInvalidKey
+	}
+
+	if len(ciphertext) < boxHeaderSize {
+		return nil, ErrEncryptedTooShort
+	}
+
+	pub, err := box.PrivateKey.Public()
+	if err != nil {
+		return nil, err
+	}
+
+	header, sealed := ciphertext[:boxHeaderSize], ciphertext[boxHeaderSize:]
+
+	shared, err := curve25519.X25519(box.PrivateKey, header[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := boxAEAD(BoxSuite(header[0]), shared, header[1:], pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAEAD(aead, sealed, append(header[:boxHeaderSize:boxHeaderSize], aad...))
+}
+
+// boxAEAD is helper function to derive AEAD key from shared secret and both public keys
+func boxAEAD(suite BoxSuite, shared, ephemeralPub, recipientPub []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, 2*curve25519.PointSize)
+	salt = append(salt, ephemeralPub...)
+	salt = append(salt, recipientPub...)
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, append([]byte("x25519 sealed box "), byte(suite))), key); err != nil {
+		return nil, err
+	}
+
+	switch suite {
+	case BoxAESGCM:
+		return newGCM(key)
+	case BoxChaCha20:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, errors.New("unsupported box suite")
+	}
+}
@@ -0,0 +1,76 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestSealedBoxEncryptDecrypt(t *testing.T) {
+	priv, err := crypto.GenerateX25519Key()
+	assert.NoError(t, err)
+
+	pub, err := priv.Public()
+	assert.NoError(t, err)
+
+	for _, suite := range []crypto.BoxSuite{0, crypto.BoxAESGCM, crypto.BoxChaCha20} {
+		sender := &crypto.Cipher{
+			Crypter: &crypto.SealedBox{
+				PublicKey: pub,
+				Suite:     suite,
+			},
+		}
+
+		err := sender.Encrypt([]byte(message))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, sender.Hex)
+		assert.NotEmpty(t, sender.Base64)
+
+		recipient := &crypto.Cipher{
+			Crypter: &crypto.SealedBox{
+				PrivateKey: priv,
+			},
+		}
+
+		err = recipient.Decrypt(sender.Encrypted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, message, recipient.Decrypted)
+	}
+}
+
+func TestSealedBoxWrongKey(t *testing.T) {
+	priv, err := crypto.GenerateX25519Key()
+	assert.NoError(t, err)
+
+	pub, err := priv.Public()
+	assert.NoError(t, err)
+
+	other, err := crypto.GenerateX25519Key()
+	assert.NoError(t, err)
+
+	sealed, err := (&crypto.SealedBox{PublicKey: pub}).Seal([]byte(message), []byte("aad"))
+	assert.NoError(t, err)
+
+	_, err = (&crypto.SealedBox{PrivateKey: other}).Open(sealed, []byte("aad"))
+	assert.Error(t, err)
+
+	_, err = (&crypto.SealedBox{PrivateKey: priv}).Open(sealed, []byte("other"))
+	assert.Error(t, err)
+
+	opened, err := (&crypto.SealedBox{PrivateKey: priv}).Open(sealed, []byte("aad"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, string(opened))
+}
+
+func TestSealedBoxMissingKey(t *testing.T) {
+	_, _, _, err := (&crypto.SealedBox{}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+
+	_, err = (&crypto.SealedBox{}).Decrypt(message)
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+}
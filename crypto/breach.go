@@ -0,0 +1,285 @@
+package crypto
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// rangePrefixLen is length of SHA-1 hex prefix sent to RangeSource
+const rangePrefixLen = 5
+
+// pwnedPasswordsURL is Have I Been Pwned range API
+const pwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+// ErrPasswordBreached error
+var ErrPasswordBreached = errors.New("password found in data breaches")
+
+// RangeSource returns breached password hashes sharing SHA-1 prefix (k-anonymity)
+// Only first 5 hex characters of password SHA-1 hash are revealed to the source
+type RangeSource interface {
+	// Range returns uppercase hex SHA-1 suffixes (without prefix) and their breach counts for uppercase hex prefix
+	Range(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+// BreachChecker checks passwords against breached password hashes
+type BreachChecker struct {
+	Source RangeSource
+	// Threshold is breach count from which password is considered breached, 1 if not set
+	Threshold int
+}
+
+// FileRangeSource reads breached password hashes from local file
+// Each line is uppercase hex SHA-1 hash and breach count: HASH:COUNT, as in Pwned Passwords SHA-1 download
+// Lines must be sorted by hash (download ordered by hash), range is found by binary search instead of reading whole file
+type FileRangeSource struct {
+	Path string
+}
+
+// HTTPRangeSource queries range API, GET URL + prefix, response lines are SUFFIX:COUNT
+type HTTPRangeSource struct {
+	// URL of range API, Pwned Passwords API if not set
+	URL    string
+	Client *http.Client
+}
+
+// NewBreachChecker will initialize BreachChecker using Pwned Passwords API
+func NewBreachChecker() *BreachChecker {
+	return &BreachChecker{
+		Source: &HTTPRangeSource{},
+	}
+}
+
+// Count will return how many times password was found in breaches
+func (checker *BreachChecker) Count(ctx context.Context, password string) (int, error) {
+	if checker.Source == nil {
+		return 0, errors.New("missing range source")
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := checker.Source.Range(ctx, hash[:rangePrefixLen])
+	if err != nil {
+		return 0, err
+	}
+
+	return suffixes[hash[rangePrefixLen:]], nil
+}
+
+// Check will return ErrPasswordBreached if password breach count reaches Threshold
+func (checker *BreachChecker) Check(ctx context.Context, password string) error {
+	count, err := checker.Count(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	threshold := checker.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	if count >= threshold {
+		return fmt.Errorf("%w: seen %d times", ErrPasswordBreached, count)
+	}
+
+	return nil
+}
+
+// Range implements RangeSource.Range
+func (source *FileRangeSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = strings.ToUpper(prefix)
+
+	// lowest offset from which first line has prefix greater or equal to searched prefix
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		mid := lo + (hi-lo)/2
+
+		reader, err := lineReaderAt(file, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		line, err := readRangeLine(reader)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if err == nil && linePrefix(line) < prefix {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	reader, err := lineReaderAt(file, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes := make(map[string]int)
+
+	for {
+		line, err := readRangeLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if linePrefix(line) != prefix {
+			break
+		}
+
+		suffix, count, err := parseRangeLine(line[rangePrefixLen:])
+		if err != nil {
+			return nil, err
+		}
+
+		suffixes[suffix] = count
+	}
+
+	return suffixes, ctx.Err()
+}
+
+// lineReaderAt is helper function to get reader positioned at first line starting at or after offset
+func lineReaderAt(file *os.File, offset int64) (*bufio.Reader, error) {
+	if offset == 0 {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		return bufio.NewReader(file), nil
+	}
+
+	// previous byte is read too, so line starting exactly at offset is not skipped
+	if _, err := file.Seek(offset-1, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(file)
+	if _, err := reader.ReadString('\n'); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// readRangeLine is helper function to read next non-empty line, io.EOF is returned if there is none
+func readRangeLine(reader *bufio.Reader) (string, error) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+
+		line = strings.TrimSpace(line)
+		if line != "" {
+			return line, nil
+		}
+
+		if err == io.EOF {
+			return "", io.EOF
+		}
+	}
+}
+
+// linePrefix is helper function to get uppercase hash prefix of HASH:COUNT line
+func linePrefix(line string) string {
+	if len(line) < rangePrefixLen {
+		return strings.ToUpper(line)
+	}
+
+	return strings.ToUpper(line[:rangePrefixLen])
+}
+
+// Range implements RangeSource.Range
+func (source *HTTPRangeSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	url := source.URL
+	if url == "" {
+		url = pwnedPasswordsURL
+	}
+
+	client := source.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("range source responded with status %d", resp.StatusCode)
+	}
+
+	return readRange(resp.Body)
+}
+
+// readRange is helper function to read SUFFIX:COUNT lines
+func readRange(r io.Reader) (map[string]int, error) {
+	suffixes := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		suffix, count, err := parseRangeLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		suffixes[suffix] = count
+	}
+
+	return suffixes, scanner.Err()
+}
+
+// parseRangeLine is helper function to parse SUFFIX:COUNT line
+func parseRangeLine(line string) (string, int, error) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid range line %q", line)
+	}
+
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid range line %q", line)
+	}
+
+	return strings.ToUpper(parts[0]), count, nil
+}
@@ -0,0 +1,160 @@
+package crypto_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const breachedRange = `0018A45C4D1DEF81644B54AB7F969B88D65:1
+1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493
+011053FD0102E94D6AE2F8B83D76FAF94F6:0
+`
+
+func TestBreachCheckerHTTP(t *testing.T) {
+	prefixes := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefixes <- r.URL.Path
+		fmt.Fprint(w, breachedRange)
+	}))
+	defer server.Close()
+
+	checker := &crypto.BreachChecker{
+		Source: &crypto.HTTPRangeSource{
+			URL:    server.URL + "/range/",
+			Client: server.Client(),
+		},
+	}
+
+	count, err := checker.Count(context.Background(), "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+
+	err = checker.Check(context.Background(), "password")
+	assert.True(t, errors.Is(err, crypto.ErrPasswordBreached))
+
+	assert.NoError(t, checker.Check(context.Background(), "Tr0ub4dor&3x"))
+
+	// only hash prefix is sent
+	assert.Equal(t, "/range/5BAA6", <-prefixes)
+}
+
+func TestBreachCheckerThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, breachedRange)
+	}))
+	defer server.Close()
+
+	checker := &crypto.BreachChecker{
+		Source:    &crypto.HTTPRangeSource{URL: server.URL + "/"},
+		Threshold: 5000000,
+	}
+
+	assert.NoError(t, checker.Check(context.Background(), "password"))
+}
+
+func TestBreachCheckerHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	checker := &crypto.BreachChecker{
+		Source: &crypto.HTTPRangeSource{URL: server.URL + "/"},
+	}
+
+	assert.Error(t, checker.Check(context.Background(), "password"))
+}
+
+func TestBreachCheckerFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "breached")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString("000000005AD76BD555C1D6D771DE417A4B87E4B4:4\n" +
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\n" +
+		"5BAA6FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:2\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	checker := &crypto.BreachChecker{
+		Source: &crypto.FileRangeSource{Path: file.Name()},
+	}
+
+	count, err := checker.Count(context.Background(), "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3861493, count)
+
+	count, err = checker.Count(context.Background(), "Tr0ub4dor&3x")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestFileRangeSourceSorted(t *testing.T) {
+	file, err := ioutil.TempFile("", "breached")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	// every prefix divisible by 3 has (prefix % 4) + 1 hashes, last line has no newline
+	expected := make(map[string]map[string]int)
+
+	for i := 0; i < 3000; i += 3 {
+		prefix := fmt.Sprintf("%05X", i)
+		expected[prefix] = make(map[string]int)
+
+		for j := 0; j <= i%4; j++ {
+			suffix := fmt.Sprintf("%035X", j)
+			expected[prefix][suffix] = i + j
+
+			_, err = fmt.Fprintf(file, "%s%s:%d\r\n", prefix, suffix, i+j)
+			assert.NoError(t, err)
+		}
+	}
+
+	_, err = file.WriteString("FFFFF00000000000000000000000000000000000:7")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	source := &crypto.FileRangeSource{Path: file.Name()}
+
+	for _, i := range []int{0, 3, 6, 1500, 2997} {
+		prefix := fmt.Sprintf("%05X", i)
+
+		suffixes, err := source.Range(context.Background(), prefix)
+
+		assert.NoError(t, err, prefix)
+		assert.Equal(t, expected[prefix], suffixes, prefix)
+	}
+
+	for _, prefix := range []string{"00001", "00BB6", "EEEEE"} {
+		suffixes, err := source.Range(context.Background(), prefix)
+
+		assert.NoError(t, err, prefix)
+		assert.Empty(t, suffixes, prefix)
+	}
+
+	suffixes, err := source.Range(context.Background(), "fffff")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"00000000000000000000000000000000000": 7}, suffixes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = source.Range(ctx, "00003")
+	assert.Equal(t, context.Canceled, err)
+}
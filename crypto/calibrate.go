@@ -0,0 +1,210 @@
+package crypto
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// calibrationPlain is hashed while measuring params
+	calibrationPlain = "calibration-password"
+	// argon2MinMemory is lowest Argon2 memory (KiB) calibration will go down to
+	argon2MinMemory = 1024
+	// argon2MaxTime is highest Argon2 passes calibration will go up to
+	argon2MaxTime = 1 << 16
+	// sCryptMinN is lowest SCrypt N calibration starts with
+	sCryptMinN = 1 << 10
+)
+
+var (
+	// ErrInvalidTarget error
+	ErrInvalidTarget = errors.New("calibration target must be greater than 0")
+	// ErrTargetTooLow error, returned when even the lowest params take longer than target
+	ErrTargetTooLow = errors.New("calibration target too low for lowest params")
+)
+
+// CalibrateArgon2 will benchmark Argon2 on current machine and return it with params hashing within target
+// Threads are set to number of CPUs (at most 255),
+// Memory (KiB) is set to maxMemoryKiB (at most 4 GiB) and halved down to 1 MiB only if single pass exceeds target,
+// Time is then set to highest number of passes within target,
+// ErrTargetTooLow is returned if single pass with 1 MiB exceeds target
+func CalibrateArgon2(target time.Duration, maxMemoryKiB uint32) (*Argon2, error) {
+	if target <= 0 {
+		return nil, ErrInvalidTarget
+	}
+
+	argon := NewArgon2()
+
+	if maxMemoryKiB < argon2MinMemory {
+		return nil, errors.New("argon2 memory ceiling must be at least 1 MiB")
+	}
+
+	argon.Threads = math.MaxUint8
+	if cpus := runtime.NumCPU(); cpus < math.MaxUint8 {
+		argon.Threads = uint8(cpus)
+	}
+
+	// hashes with more memory are rejected when decoded
+	if maxMemoryKiB > argon2MaxMemory {
+		maxMemoryKiB = argon2MaxMemory
+	}
+
+	argon.Memory = maxMemoryKiB
+	argon.Time = 1
+
+	var elapsed time.Duration
+	for {
+		d, err := measure(argon)
+		if err != nil {
+			return nil, err
+		}
+
+		elapsed = d
+
+		if elapsed <= target || argon.Memory/2 < argon2MinMemory {
+			break
+		}
+
+		argon.Memory /= 2
+	}
+
+	if elapsed > target {
+		return nil, ErrTargetTooLow
+	}
+
+	// clock can be too coarse to measure single pass, passes are doubled until it is measurable
+	for elapsed == 0 && argon.Time < argon2MaxTime {
+		argon.Time *= 2
+
+		d, err := measure(argon)
+		if err != nil {
+			return nil, err
+		}
+
+		elapsed = d
+	}
+
+	if elapsed == 0 {
+		return argon, nil
+	}
+
+	// running time grows linearly with passes
+	passes := uint64(target) * uint64(argon.Time) / uint64(elapsed)
+	if passes > argon2MaxTime {
+		passes = argon2MaxTime
+	}
+
+	if passes < 1 {
+		passes = 1
+	}
+
+	if uint32(passes) != argon.Time {
+		argon.Time = uint32(passes)
+
+		d, err := measure(argon)
+		if err != nil {
+			return nil, err
+		}
+
+		if d > target && argon.Time > 1 {
+			argon.Time--
+		}
+	}
+
+	return argon, nil
+}
+
+// CalibrateSCrypt will benchmark SCrypt on current machine and return it with highest power of 2 N
+// hashing within target and not using more than maxMemoryBytes (128 * N * R),
+// ErrTargetTooLow is returned if even N of 1024 exceeds target
+func CalibrateSCrypt(target time.Duration, maxMemoryBytes int) (*SCrypt, error) {
+	if target <= 0 {
+		return nil, ErrInvalidTarget
+	}
+
+	sCrypt := NewSCrypt()
+	sCrypt.N = sCryptMinN
+
+	if 128*sCrypt.N*sCrypt.R > maxMemoryBytes {
+		return nil, errors.New("scrypt memory ceiling is too low")
+	}
+
+	d, err := measure(sCrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	if d > target {
+		return nil, ErrTargetTooLow
+	}
+
+	for 128*2*sCrypt.N*sCrypt.R <= maxMemoryBytes {
+		next := *sCrypt
+		next.N *= 2
+
+		d, err := measure(&next)
+		if err != nil {
+			return nil, err
+		}
+
+		if d > target {
+			break
+		}
+
+		sCrypt.N = next.N
+	}
+
+	return sCrypt, nil
+}
+
+// CalibrateBCrypt will benchmark BCrypt on current machine and return it with highest Cost hashing within target
+// ErrTargetTooLow is returned if even bcrypt.MinCost exceeds target
+func CalibrateBCrypt(target time.Duration) (*BCrypt, error) {
+	if target <= 0 {
+		return nil, ErrInvalidTarget
+	}
+
+	bCrypt := NewBCrypt()
+	bCrypt.Cost = bcrypt.MinCost
+
+	d, err := measure(bCrypt)
+	if err != nil {
+		return nil, err
+	}
+
+	if d > target {
+		return nil, ErrTargetTooLow
+	}
+
+	for bCrypt.Cost < bcrypt.MaxCost {
+		next := &BCrypt{Cost: bCrypt.Cost + 1}
+
+		d, err := measure(next)
+		if err != nil {
+			return nil, err
+		}
+
+		if d > target {
+			break
+		}
+
+		bCrypt.Cost = next.Cost
+	}
+
+	return bCrypt, nil
+}
+
+// measure is helper function to measure how long hasher takes to encode calibrationPlain
+func measure(hasher PasswordHasher) (time.Duration, error) {
+	start := time.Now()
+
+	if _, err := hasher.Encode(calibrationPlain); err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
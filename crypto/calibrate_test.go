@@ -0,0 +1,96 @@
+package crypto_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestCalibrateArgon2(t *testing.T) {
+	argon, err := crypto.CalibrateArgon2(20*time.Millisecond, 4*1024)
+
+	assert.NoError(t, err)
+	assert.True(t, argon.Memory >= 1024 && argon.Memory <= 4*1024)
+	assert.True(t, argon.Time >= 1)
+
+	threads := runtime.NumCPU()
+	if threads > 255 {
+		threads = 255
+	}
+
+	assert.Equal(t, uint8(threads), argon.Threads)
+
+	encoded, err := argon.Encode(message)
+	assert.NoError(t, err)
+
+	valid, err := argon.Verify(message, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestCalibrateArgon2Invalid(t *testing.T) {
+	_, err := crypto.CalibrateArgon2(0, 4*1024)
+	assert.Equal(t, crypto.ErrInvalidTarget, err)
+
+	_, err = crypto.CalibrateArgon2(time.Millisecond, 512)
+	assert.Error(t, err)
+
+	_, err = crypto.CalibrateArgon2(time.Nanosecond, 1024)
+	assert.Equal(t, crypto.ErrTargetTooLow, err)
+}
+
+func TestCalibrateSCrypt(t *testing.T) {
+	maxMemory := 128 * 4096 * 8
+
+	sCrypt, err := crypto.CalibrateSCrypt(50*time.Millisecond, maxMemory)
+
+	assert.NoError(t, err)
+	assert.True(t, sCrypt.N >= 1024 && sCrypt.N <= 4096)
+	assert.Equal(t, 0, sCrypt.N&(sCrypt.N-1))
+
+	encoded, err := sCrypt.Encode(message)
+	assert.NoError(t, err)
+
+	valid, err := sCrypt.Verify(message, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestCalibrateSCryptInvalid(t *testing.T) {
+	_, err := crypto.CalibrateSCrypt(-time.Millisecond, 1<<20)
+	assert.Equal(t, crypto.ErrInvalidTarget, err)
+
+	_, err = crypto.CalibrateSCrypt(time.Millisecond, 1024)
+	assert.Error(t, err)
+
+	_, err = crypto.CalibrateSCrypt(time.Nanosecond, 1<<20)
+	assert.Equal(t, crypto.ErrTargetTooLow, err)
+}
+
+func TestCalibrateBCrypt(t *testing.T) {
+	bCrypt, err := crypto.CalibrateBCrypt(20 * time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.True(t, bCrypt.Cost >= bcrypt.MinCost && bCrypt.Cost <= bcrypt.MaxCost)
+
+	encoded, err := bCrypt.Encode(message)
+	assert.NoError(t, err)
+
+	valid, err := bCrypt.Verify(message, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = crypto.CalibrateBCrypt(0)
+	assert.Equal(t, crypto.ErrInvalidTarget, err)
+
+	_, err = crypto.CalibrateBCrypt(time.Nanosecond)
+	assert.Equal(t, crypto.ErrTargetTooLow, err)
+}
@@ -11,6 +11,13 @@ import (
 	"github.com/semirm-dev/godev/str"
 )
 
+var (
+	// ErrInvalidIV error
+	ErrInvalidIV = errors.New("iv must be one block (16 bytes) long")
+	// ErrNotFullBlocks error
+	ErrNotFullBlocks = errors.New("encrypted text is not a multiple of the block size")
+)
+
 // CBC crypter
 type CBC struct {
 	Secret string
@@ -20,7 +27,7 @@ type CBC struct {
 // Encrypt payload using AES encryption CBC mode
 func (cbcEnc *CBC) Encrypt(payload []byte) (string, string, string, error) {
 	if strings.TrimSpace(cbcEnc.Secret) == "" {
-		return "", "", "", errors.New("secret key not provided")
+		return "", "", "", ErrMissingSecret
 	}
 
 	key := []byte(cbcEnc.Secret)
@@ -30,12 +37,15 @@ func (cbcEnc *CBC) Encrypt(payload []byte) (string, string, string, error) {
 		return "", "", "", err
 	}
 
+	byteIV := []byte(cbcEnc.IV)
+	if len(byteIV) != aes.BlockSize {
+		return "", "", "", ErrInvalidIV
+	}
+
 	byteIn := pkcsPad(payload, aes.BlockSize)
 
 	encrypted := make([]byte, len(byteIn))
 
-	byteIV := []byte(cbcEnc.IV)
-
 	mode := cipher.NewCBCEncrypter(block, byteIV)
 	mode.CryptBlocks(encrypted, byteIn)
 
@@ -45,7 +55,7 @@ func (cbcEnc *CBC) Encrypt(payload []byte) (string, string, string, error) {
 // Decrypt AES CBC encrypted input
 func (cbcEnc *CBC) Decrypt(encrypted string) (string, error) {
 	if strings.TrimSpace(cbcEnc.Secret) == "" {
-		return "", errors.New("secret key not provided")
+		return "", ErrMissingSecret
 	}
 
 	key := []byte(cbcEnc.Secret)
@@ -55,14 +65,21 @@ func (cbcEnc *CBC) Decrypt(encrypted string) (string, error) {
 		return "", err
 	}
 
+	byteIV := []byte(cbcEnc.IV)
+	if len(byteIV) != aes.BlockSize {
+		return "", ErrInvalidIV
+	}
+
 	byteIn := []byte(encrypted)
 	if len(byteIn) < aes.BlockSize {
-		return "", errors.New("encrypted text too short")
+		return "", ErrEncryptedTooShort
 	}
 
-	decrypted := make([]byte, len(byteIn))
+	if len(byteIn)%aes.BlockSize != 0 {
+		return "", ErrNotFullBlocks
+	}
 
-	byteIV := []byte(cbcEnc.IV)
+	decrypted := make([]byte, len(byteIn))
 
 	mode := cipher.NewCBCDecrypter(block, byteIV)
 	mode.CryptBlocks(decrypted, byteIn)
@@ -94,7 +111,7 @@ func pkcsUnPad(input []byte, blockSize int) ([]byte, error) {
 
 	pad := input[inputLen-1]
 	padLen := int(pad)
-	if padLen > inputLen || padLen > blockSize {
+	if padLen == 0 || padLen > inputLen || padLen > blockSize {
 		return nil, errors.New("cryptgo/padding: invalid padding size")
 	}
 
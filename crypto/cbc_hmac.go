@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// cbcHmacVersion of encrypted format, it makes versioned ciphertext one byte longer than whole blocks
+const cbcHmacVersion byte = 1
+
+// ErrInvalidMAC error
+var ErrInvalidMAC = errors.New("message authentication failed")
+
+// CBCHMAC crypter, AES CBC mode with HMAC-SHA256 (encrypt-then-MAC) and random IV per message
+// Encryption and MAC keys are derived from Secret with HKDF-SHA256, Secret must be compatible with AES-128 or AES-256
+// Format: version (1 byte) | IV | encrypted | HMAC-SHA256(aad | version | IV | encrypted | aad bit length)
+type CBCHMAC struct {
+	Secret string
+	// Legacy crypter is used only when explicitly set, to decrypt ciphertexts produced by CBC with fixed IV
+	// Only inputs of whole blocks (without version byte) are decrypted by Legacy, never inputs failing authentication
+	Legacy *CBC
+}
+
+// Encrypt payload using AES CBC with HMAC-SHA256
+func (cbcHmac *CBCHMAC) Encrypt(payload []byte) (string, string, string, error) {
+	encrypted, err := cbcHmac.Seal(payload, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt AES CBC with HMAC-SHA256 encrypted input
+// If Legacy crypter is set and input is not in versioned format, it is decrypted as legacy fixed IV ciphertext
+func (cbcHmac *CBCHMAC) Decrypt(encrypted string) (string, error) {
+	byteIn := []byte(encrypted)
+
+	// legacy ciphertexts are whole blocks, versioned ones have additional version byte
+	if cbcHmac.Legacy != nil && len(byteIn)%aes.BlockSize == 0 {
+		return cbcHmac.Legacy.Decrypt(encrypted)
+	}
+
+	decrypted, err := cbcHmac.Open(byteIn, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// Seal implements AEAD.Seal
+func (cbcHmac *CBCHMAC) Seal(plaintext, aad []byte) ([]byte, error) {
+	block, macKey, err := cbcHmac.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	byteIn := pkcsPad(append([]byte(nil), plaintext...), aes.BlockSize)
+
+	encrypted := make([]byte, 1+aes.BlockSize+len(byteIn), 1+aes.BlockSize+len(byteIn)+sha256.Size)
+	encrypted[0] = cbcHmacVersion
+
+	iv := encrypted[1 : 1+aes.BlockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	mode := cipher.NewCBCEncrypter(block, iv)
+	mode.CryptBlocks(encrypted[1+aes.BlockSize:], byteIn)
+
+	return append(encrypted, cbcMAC(macKey, aad, encrypted)...), nil
+}
+
+// Open implements AEAD.Open, MAC is verified in constant time before unpadding
+func (cbcHmac *CBCHMAC) Open(ciphertext, aad []byte) ([]byte, error) {
+	block, macKey, err := cbcHmac.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < 1+2*aes.BlockSize+sha256.Size {
+		return nil, ErrEncryptedTooShort
+	}
+
+	encrypted, mac := ciphertext[:len(ciphertext)-sha256.Size], ciphertext[len(ciphertext)-sha256.Size:]
+
+	if !hmac.Equal(mac, cbcMAC(macKey, aad, encrypted)) {
+		return nil, ErrInvalidMAC
+	}
+
+	if encrypted[0] != cbcHmacVersion {
+		return nil, errors.New("unsupported cbc-hmac version")
+	}
+
+	iv, encrypted := encrypted[1:1+aes.BlockSize], encrypted[1+aes.BlockSize:]
+	if len(encrypted)%aes.BlockSize != 0 {
+		return nil, ErrNotFullBlocks
+	}
+
+	decrypted := make([]byte, len(encrypted))
+
+	mode := cipher.NewCBCDecrypter(block, iv)
+	mode.CryptBlocks(decrypted, encrypted)
+
+	return pkcsUnPad(decrypted, aes.BlockSize)
+}
+
+// keys is helper function to derive AES block cipher and MAC key from Secret
+func (cbcHmac *CBCHMAC) keys() (cipher.Block, []byte, error) {
+	if strings.TrimSpace(cbcHmac.Secret) == "" {
+		return nil, nil, ErrMissingSecret
+	}
+
+	secret := []byte(cbcHmac.Secret)
+
+	encKey := make([]byte, len(secret))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte("cbc-hmac-sha256 encryption")), encKey); err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	macKey := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte("cbc-hmac-sha256 authentication")), macKey); err != nil {
+		return nil, nil, err
+	}
+
+	return block, macKey, nil
+}
+
+// cbcMAC is helper function to calculate HMAC-SHA256 over aad, version, IV and encrypted text
+func cbcMAC(macKey, aad, encrypted []byte) []byte {
+	aadLen := make([]byte, 8)
+	binary.BigEndian.PutUint64(aadLen, uint64(len(aad))*8)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(aad)
+	mac.Write(encrypted)
+	mac.Write(aadLen)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestCBCHMACEncryptDecrypt(t *testing.T) {
+	cipher := &crypto.Cipher{
+		Crypter: &crypto.CBCHMAC{
+			Secret: secret,
+		},
+	}
+
+	err := cipher.Encrypt([]byte(message))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cipher.Hex)
+	assert.NotEmpty(t, cipher.Base64)
+	assert.Len(t, cipher.Encrypted, 1+16+16+32)
+
+	err = cipher.Decrypt(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, cipher.Decrypted)
+}
+
+func TestCBCHMACRandomIV(t *testing.T) {
+	cbcHmac := &crypto.CBCHMAC{
+		Secret: secret,
+	}
+
+	first, _, _, err := cbcHmac.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	second, _, _, err := cbcHmac.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestCBCHMACTampered(t *testing.T) {
+	cbcHmac := &crypto.CBCHMAC{
+		Secret: secret,
+	}
+
+	encrypted, err := cbcHmac.Seal([]byte(message), []byte("aad"))
+	assert.NoError(t, err)
+
+	for _, i := range []int{0, 1, 17, len(encrypted) - 1} {
+		tampered := append([]byte(nil), encrypted...)
+		tampered[i] ^= 1
+
+		_, err := cbcHmac.Open(tampered, []byte("aad"))
+
+		assert.Equal(t, crypto.ErrInvalidMAC, err)
+	}
+
+	_, err = cbcHmac.Open(encrypted, []byte("other"))
+	assert.Equal(t, crypto.ErrInvalidMAC, err)
+
+	_, err = cbcHmac.Open(encrypted[:40], nil)
+	assert.Equal(t, crypto.ErrEncryptedTooShort, err)
+
+	opened, err := cbcHmac.Open(encrypted, []byte("aad"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, string(opened))
+}
+
+func TestCBCHMACLegacy(t *testing.T) {
+	legacy := &crypto.CBC{
+		Secret: secret,
+		IV:     iv,
+	}
+
+	_, err := (&crypto.CBCHMAC{Secret: secret}).Decrypt(cbcEncrypted)
+	assert.Error(t, err)
+
+	cbcHmac := &crypto.CBCHMAC{
+		Secret: secret,
+		Legacy: legacy,
+	}
+
+	decrypted, err := cbcHmac.Decrypt(cbcEncrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	encrypted, _, _, err := cbcHmac.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	decrypted, err = cbcHmac.Decrypt(encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+}
+
+func TestCBCHMACLegacyNoFallback(t *testing.T) {
+	cbcHmac := &crypto.CBCHMAC{
+		Secret: secret,
+		Legacy: &crypto.CBC{
+			Secret: secret,
+			IV:     iv,
+		},
+	}
+
+	encrypted, _, _, err := cbcHmac.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	// forged versioned ciphertext never reaches legacy decryption
+	tampered := []byte(encrypted)
+	tampered[20] ^= 1
+
+	_, err = cbcHmac.Decrypt(string(tampered))
+	assert.Equal(t, crypto.ErrInvalidMAC, err)
+
+	for _, n := range []int{1, 20, 70} {
+		_, err = cbcHmac.Decrypt(string(make([]byte, n)))
+		assert.Error(t, err, n)
+	}
+}
+
+func TestCBCHMACMissingSecret(t *testing.T) {
+	_, _, _, err := (&crypto.CBCHMAC{}).Encrypt([]byte(message))
+
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+}
@@ -43,3 +43,24 @@ func TestCBCDecrypt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, message, cipher.Decrypted)
 }
+
+func TestCBCDecryptInvalid(t *testing.T) {
+	cbc := &crypto.CBC{
+		Secret: secret,
+		IV:     iv,
+	}
+
+	_, err := cbc.Decrypt("")
+	assert.Equal(t, crypto.ErrEncryptedTooShort, err)
+
+	for _, n := range []int{20, 70} {
+		_, err = cbc.Decrypt(string(make([]byte, n)))
+		assert.Equal(t, crypto.ErrNotFullBlocks, err, n)
+	}
+
+	_, err = (&crypto.CBC{Secret: secret, IV: "short"}).Decrypt(cbcEncrypted)
+	assert.Equal(t, crypto.ErrInvalidIV, err)
+
+	_, _, _, err = (&crypto.CBC{Secret: secret, IV: "short"}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrInvalidIV, err)
+}
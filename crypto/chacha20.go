@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// ChaCha20 crypter, ChaCha20-Poly1305 (RFC 8439) with 96bit random nonce
+// Secret must be 256bit
+type ChaCha20 struct {
+	Secret string
+}
+
+// XChaCha20 crypter, XChaCha20-Poly1305 with 192bit random nonce, safe for large number of messages per key
+// Secret must be 256bit
+type XChaCha20 struct {
+	Secret string
+}
+
+// Encrypt payload using ChaCha20-Poly1305
+func (chacha *ChaCha20) Encrypt(payload []byte) (string, string, string, error) {
+	encrypted, err := chacha.Seal(payload, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt ChaCha20-Poly1305 encrypted input
+func (chacha *ChaCha20) Decrypt(encrypted string) (string, error) {
+	decrypted, err := chacha.Open([]byte(encrypted), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// Seal implements AEAD.Seal, random nonce is prepended to encrypted plaintext
+func (chacha *ChaCha20) Seal(plaintext, aad []byte) ([]byte, error) {
+	aead, err := newChaCha20([]byte(chacha.Secret), chacha20poly1305.New)
+	if err != nil {
+		return nil, err
+	}
+
+	return sealAEAD(aead, plaintext, aad)
+}
+
+// Open implements AEAD.Open
+func (chacha *ChaCha20) Open(ciphertext, aad []byte) ([]byte, error) {
+	aead, err := newChaCha20([]byte(chacha.Secret), chacha20poly1305.New)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAEAD(aead, ciphertext, aad)
+}
+
+// Encrypt payload using XChaCha20-Poly1305
+func (xchacha *XChaCha20) Encrypt(payload []byte) (string, string, string, error) {
+	encrypted, err := xchacha.Seal(payload, nil)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt XChaCha20-Poly1305 encrypted input
+func (xchacha *XChaCha20) Decrypt(encrypted string) (string, error) {
+	decrypted, err := xchacha.Open([]byte(encrypted), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// Seal implements AEAD.Seal, random nonce is prepended to encrypted plaintext
+func (xchacha *XChaCha20) Seal(plaintext, aad []byte) ([]byte, error) {
+	aead, err := newChaCha20([]byte(xchacha.Secret), chacha20poly1305.NewX)
+	if err != nil {
+		return nil, err
+	}
+
+	return sealAEAD(aead, plaintext, aad)
+}
+
+// Open implements AEAD.Open
+func (xchacha *XChaCha20) Open(ciphertext, aad []byte) ([]byte, error) {
+	aead, err := newChaCha20([]byte(xchacha.Secret), chacha20poly1305.NewX)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAEAD(aead, ciphertext, aad)
+}
+
+// newChaCha20 is helper function to create ChaCha20-Poly1305 or XChaCha20-Poly1305 cipher
+func newChaCha20(key []byte, fn func([]byte) (cipher.AEAD, error)) (cipher.AEAD, error) {
+	if len(bytes.TrimSpace(key)) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	return fn(key)
+}
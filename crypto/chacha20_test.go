@@ -0,0 +1,96 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+// Known answer test vectors:
+// ChaCha20-Poly1305: RFC 8439, section 2.8.2
+// XChaCha20-Poly1305: draft-irtf-cfrg-xchacha, appendix A.3.1
+var (
+	chachaPlain = "Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."
+	chachaAAD   = "50515253c0c1c2c3c4c5c6c7"
+	chachaKey   = "808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f"
+
+	chachaNonce     = "070000004041424344454647"
+	chachaEncrypted = "d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d63dbea45e8ca9671282fafb69da92728b1a71de0a9e060b2905d6a5b67ecd3b3692ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc3ff4def08e4b7a9de576d26586cec64b6116" +
+		"1ae10b594f09e26a7e902ecbd0600691"
+
+	xchachaNonce     = "404142434445464748494a4b4c4d4e4f5051525354555657"
+	xchachaEncrypted = "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff921f9664c97637da9768812f615c68b13b52e" +
+		"c0875924c1c7987947deafd8780acf49"
+)
+
+func TestChaCha20KnownAnswer(t *testing.T) {
+	chacha := &crypto.ChaCha20{
+		Secret: string(decodeHex(t, chachaKey)),
+	}
+
+	opened, err := chacha.Open(decodeHex(t, chachaNonce+chachaEncrypted), decodeHex(t, chachaAAD))
+
+	assert.NoError(t, err)
+	assert.Equal(t, chachaPlain, string(opened))
+}
+
+func TestXChaCha20KnownAnswer(t *testing.T) {
+	xchacha := &crypto.XChaCha20{
+		Secret: string(decodeHex(t, chachaKey)),
+	}
+
+	opened, err := xchacha.Open(decodeHex(t, xchachaNonce+xchachaEncrypted), decodeHex(t, chachaAAD))
+
+	assert.NoError(t, err)
+	assert.Equal(t, chachaPlain, string(opened))
+
+	_, err = xchacha.Open(decodeHex(t, xchachaNonce+xchachaEncrypted), nil)
+
+	assert.Error(t, err)
+}
+
+func TestChaCha20EncryptDecrypt(t *testing.T) {
+	crypters := []crypto.Crypter{
+		&crypto.ChaCha20{Secret: secret},
+		&crypto.XChaCha20{Secret: secret},
+	}
+
+	for _, crypter := range crypters {
+		cipher := &crypto.Cipher{
+			Crypter: crypter,
+		}
+
+		err := cipher.Encrypt([]byte(message))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, cipher.Encrypted)
+		assert.NotEmpty(t, cipher.Hex)
+		assert.NotEmpty(t, cipher.Base64)
+
+		err = cipher.Decrypt(cipher.Encrypted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, message, cipher.Decrypted)
+	}
+}
+
+func TestChaCha20InvalidSecret(t *testing.T) {
+	_, _, _, err := (&crypto.XChaCha20{}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+
+	_, _, _, err = (&crypto.ChaCha20{Secret: "short"}).Encrypt([]byte(message))
+	assert.Error(t, err)
+
+	_, err = (&crypto.XChaCha20{Secret: secret}).Decrypt("short")
+	assert.Equal(t, crypto.ErrEncryptedTooShort, err)
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	decoded, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+
+	return decoded
+}
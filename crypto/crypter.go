@@ -7,6 +7,12 @@ import (
 // ErrMissingCrypter error
 var ErrMissingCrypter = errors.New("missing Crypter implementation")
 
+// ErrMissingSecret error
+var ErrMissingSecret = errors.New("secret key not provided")
+
+// ErrEncryptedTooShort error
+var ErrEncryptedTooShort = errors.New("encrypted text too short")
+
 // ErrMissingPlain error
 var ErrMissingPlain = errors.New("missing Plain property")
 
@@ -47,9 +53,13 @@ func (cipher *Cipher) Encrypt(payload []byte) error {
 
 // Decrypt encrypted payload
 func (cipher *Cipher) Decrypt(encrypted string) error {
+	if cipher.Crypter == nil {
+		return ErrMissingCrypter
+	}
+
 	dec, err := cipher.Crypter.Decrypt(encrypted)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	cipher.Decrypted = dec
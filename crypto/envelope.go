@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// envelopeVersion of serialized envelope format:
+// version (1 byte) | key id length (1 byte) | key id | wrapped key length (2 bytes) | wrapped key | nonce | encrypted
+const envelopeVersion byte = 1
+
+// envelopeDataKeyLen, every payload is encrypted with its own AES-256 data key
+const envelopeDataKeyLen = 32
+
+// ErrInvalidEnvelope error
+var ErrInvalidEnvelope = errors.New("invalid envelope")
+
+// Envelope crypter encrypts each payload with random data key which is wrapped by KeyProvider
+// Wrapped data key and key id are stored together with encrypted payload
+type Envelope struct {
+	KeyProvider
+}
+
+// Encrypt payload using AES GCM encryption mode with random data key
+func (envelope *Envelope) Encrypt(payload []byte) (string, string, string, error) {
+	if envelope.KeyProvider == nil {
+		return "", "", "", errors.New("missing KeyProvider")
+	}
+
+	dataKey, err := GenerateSalt(envelopeDataKeyLen)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	keyID, wrapped, err := envelope.KeyProvider.WrapKey(dataKey)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if len(keyID) > 0xff || len(wrapped) > 0xffff {
+		return "", "", "", errors.New("key id or wrapped key too long")
+	}
+
+	header := make([]byte, 0, 4+len(keyID)+len(wrapped))
+	header = append(header, envelopeVersion, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, byte(len(wrapped)>>8), byte(len(wrapped)))
+	header = append(header, wrapped...)
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	nonce, err := GenerateSalt(gcm.NonceSize())
+	if err != nil {
+		return "", "", "", err
+	}
+
+	encrypted := append(header, nonce...)
+	encrypted = gcm.Seal(encrypted, nonce, payload, header)
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt envelope encrypted input
+func (envelope *Envelope) Decrypt(encrypted string) (string, error) {
+	if envelope.KeyProvider == nil {
+		return "", errors.New("missing KeyProvider")
+	}
+
+	byteIn := []byte(encrypted)
+
+	keyID, wrapped, header, err := parseEnvelope(byteIn)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := envelope.KeyProvider.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	byteIn = byteIn[len(header):]
+	if len(byteIn) < gcm.NonceSize() {
+		return "", ErrEncryptedTooShort
+	}
+
+	nonce, byteIn := byteIn[:gcm.NonceSize()], byteIn[gcm.NonceSize():]
+
+	decrypted, err := gcm.Open(nil, nonce, byteIn, header)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// EnvelopeKeyID will return id of key encryption key used for encrypted envelope
+func EnvelopeKeyID(encrypted string) (string, error) {
+	keyID, _, _, err := parseEnvelope([]byte(encrypted))
+
+	return keyID, err
+}
+
+// parseEnvelope is helper function to get key id, wrapped key and whole header of envelope
+func parseEnvelope(byteIn []byte) (string, []byte, []byte, error) {
+	if len(byteIn) < 2 || byteIn[0] != envelopeVersion {
+		return "", nil, nil, ErrInvalidEnvelope
+	}
+
+	keyIDEnd := 2 + int(byteIn[1])
+	if len(byteIn) < keyIDEnd+2 {
+		return "", nil, nil, ErrInvalidEnvelope
+	}
+
+	wrappedEnd := keyIDEnd + 2 + int(binary.BigEndian.Uint16(byteIn[keyIDEnd:]))
+	if len(byteIn) < wrappedEnd {
+		return "", nil, nil, ErrInvalidEnvelope
+	}
+
+	return string(byteIn[2:keyIDEnd]), byteIn[keyIDEnd+2 : wrappedEnd], byteIn[:wrappedEnd], nil
+}
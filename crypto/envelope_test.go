@@ -0,0 +1,93 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestEnvelopeEncryptDecrypt(t *testing.T) {
+	provider, err := crypto.NewMemoryKeyProvider()
+	assert.NoError(t, err)
+
+	cipher := &crypto.Cipher{
+		Crypter: &crypto.Envelope{
+			KeyProvider: provider,
+		},
+	}
+
+	err = cipher.Encrypt([]byte(message))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cipher.Encrypted)
+	assert.NotEmpty(t, cipher.Hex)
+	assert.NotEmpty(t, cipher.Base64)
+
+	keyID, err := crypto.EnvelopeKeyID(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "memory", keyID)
+
+	err = cipher.Decrypt(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, cipher.Decrypted)
+}
+
+func TestEnvelopeUniqueDataKey(t *testing.T) {
+	provider, err := crypto.NewMemoryKeyProvider()
+	assert.NoError(t, err)
+
+	envelope := &crypto.Envelope{
+		KeyProvider: provider,
+	}
+
+	first, _, _, err := envelope.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	second, _, _, err := envelope.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestEnvelopeWrongProvider(t *testing.T) {
+	provider, err := crypto.NewMemoryKeyProvider()
+	assert.NoError(t, err)
+
+	other, err := crypto.NewMemoryKeyProvider()
+	assert.NoError(t, err)
+
+	encrypted, _, _, err := (&crypto.Envelope{KeyProvider: provider}).Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	_, err = (&crypto.Envelope{KeyProvider: other}).Decrypt(encrypted)
+
+	assert.Error(t, err)
+}
+
+func TestEnvelopeTampered(t *testing.T) {
+	provider, err := crypto.NewMemoryKeyProvider()
+	assert.NoError(t, err)
+
+	envelope := &crypto.Envelope{
+		KeyProvider: provider,
+	}
+
+	encrypted, _, _, err := envelope.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	tampered := []byte(encrypted)
+	tampered[len(tampered)-1] ^= 1
+
+	_, err = envelope.Decrypt(string(tampered))
+	assert.Error(t, err)
+
+	_, err = envelope.Decrypt(encrypted[:5])
+	assert.Equal(t, crypto.ErrInvalidEnvelope, err)
+
+	_, err = envelope.Decrypt("")
+	assert.Equal(t, crypto.ErrInvalidEnvelope, err)
+}
@@ -1,13 +1,10 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"encoding/hex"
-	"errors"
-	"io"
-	"strings"
 
 	"github.com/semirm-dev/godev/str"
 )
@@ -19,63 +16,54 @@ type GCM struct {
 
 // Encrypt payload using AES GCM encryption mode
 func (gcmEnc *GCM) Encrypt(payload []byte) (string, string, string, error) {
-	if strings.TrimSpace(gcmEnc.Secret) == "" {
-		return "", "", "", errors.New("secret key not provided")
-	}
-
-	key := []byte(gcmEnc.Secret)
-
-	block, err := aes.NewCipher(key)
+	encrypted, err := gcmEnc.Seal(payload, nil)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", "", "", err
-	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", "", "", err
-	}
-
-	encrypted := gcm.Seal(nonce, nonce, payload, nil)
-
 	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
 }
 
 // Decrypt AES GCM encrypted input
 func (gcmEnc *GCM) Decrypt(payload string) (string, error) {
-	if strings.TrimSpace(gcmEnc.Secret) == "" {
-		return "", errors.New("secret key not provided")
+	decrypted, err := gcmEnc.Open([]byte(payload), nil)
+	if err != nil {
+		return "", err
 	}
 
-	key := []byte(gcmEnc.Secret)
+	return string(decrypted), nil
+}
 
-	block, err := aes.NewCipher(key)
+// Seal implements AEAD.Seal, random nonce is prepended to encrypted plaintext
+func (gcmEnc *GCM) Seal(plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM([]byte(gcmEnc.Secret))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	return sealAEAD(gcm, plaintext, aad)
+}
+
+// Open implements AEAD.Open
+func (gcmEnc *GCM) Open(ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM([]byte(gcmEnc.Secret))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	byteIn := []byte(payload)
-	nonceSize := gcm.NonceSize()
+	return openAEAD(gcm, ciphertext, aad)
+}
 
-	if len(byteIn) < nonceSize {
-		return "", errors.New("encrypted text too short")
+// newGCM is helper function to create AES GCM cipher from given key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(bytes.TrimSpace(key)) == 0 {
+		return nil, ErrMissingSecret
 	}
 
-	nonce, encrypted := byteIn[:nonceSize], byteIn[nonceSize:]
-
-	decrypted, err := gcm.Open(nil, nonce, encrypted, nil)
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(decrypted), nil
+	return cipher.NewGCM(block)
 }
@@ -0,0 +1,114 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrQueueFull error
+var ErrQueueFull = errors.New("hash pool queue is full")
+
+// HashPool runs password hashing with bounded concurrency and bounded queue
+// Only concurrency hashes are computed at a time, up to queueSize more are waiting,
+// all others are rejected with ErrQueueFull, so memory use is capped (e.g. concurrency * Argon2.Memory)
+// Hasher must not be modified by Encode/Verify, which is the case with Argon2, SCrypt, BCrypt and Registry
+type HashPool struct {
+	hasher PasswordHasher
+	// slots limit hashes computed concurrently
+	slots chan struct{}
+	// admitted limits hashes computed and waiting
+	admitted chan struct{}
+}
+
+// NewHashPool will initialize HashPool, concurrency is at least 1
+func NewHashPool(hasher PasswordHasher, concurrency, queueSize int) *HashPool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	return &HashPool{
+		hasher:   hasher,
+		slots:    make(chan struct{}, concurrency),
+		admitted: make(chan struct{}, concurrency+queueSize),
+	}
+}
+
+// Hash will encode plain once a slot is available
+// If ctx is done before hash is computed, ctx.Err() is returned while computation
+// finishes in background, still holding its slot
+func (pool *HashPool) Hash(ctx context.Context, plain string) (string, error) {
+	res, err := pool.run(ctx, func() (interface{}, error) {
+		return pool.hasher.Encode(plain)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.(string), nil
+}
+
+// Verify will verify plain against encoded once a slot is available
+func (pool *HashPool) Verify(ctx context.Context, plain, encoded string) (bool, error) {
+	res, err := pool.run(ctx, func() (interface{}, error) {
+		return pool.hasher.Verify(plain, encoded)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.(bool), nil
+}
+
+// Pending returns number of hashes computed and waiting for a slot
+func (pool *HashPool) Pending() int {
+	return len(pool.admitted)
+}
+
+// hashResult is result of hasher call
+type hashResult struct {
+	value interface{}
+	err   error
+}
+
+// run is helper function to run fn within pool limits
+func (pool *HashPool) run(ctx context.Context, fn func() (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	select {
+	case pool.admitted <- struct{}{}:
+	default:
+		return nil, ErrQueueFull
+	}
+
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		<-pool.admitted
+		return nil, ctx.Err()
+	}
+
+	done := make(chan hashResult, 1)
+
+	go func() {
+		defer func() {
+			<-pool.slots
+			<-pool.admitted
+		}()
+
+		value, err := fn()
+		done <- hashResult{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,182 @@
+package crypto_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+// blockingHasher blocks Encode until release is closed and tracks concurrent calls
+type blockingHasher struct {
+	release chan struct{}
+	started chan struct{}
+	running int32
+	max     int32
+}
+
+func newBlockingHasher() *blockingHasher {
+	return &blockingHasher{
+		release: make(chan struct{}),
+		started: make(chan struct{}, 100),
+	}
+}
+
+func (h *blockingHasher) ID() string {
+	return "blocking"
+}
+
+func (h *blockingHasher) Encode(plain string) (string, error) {
+	running := atomic.AddInt32(&h.running, 1)
+	defer atomic.AddInt32(&h.running, -1)
+
+	for {
+		max := atomic.LoadInt32(&h.max)
+		if running <= max || atomic.CompareAndSwapInt32(&h.max, max, running) {
+			break
+		}
+	}
+
+	h.started <- struct{}{}
+	<-h.release
+
+	return "$blocking$" + plain, nil
+}
+
+func (h *blockingHasher) Verify(plain, encoded string) (bool, error) {
+	return encoded == "$blocking$"+plain, nil
+}
+
+func (h *blockingHasher) NeedsRehash(encoded string) bool {
+	return false
+}
+
+func TestHashPool(t *testing.T) {
+	pool := crypto.NewHashPool(crypto.NewBCrypt(), 2, 2)
+
+	encoded, err := pool.Hash(context.Background(), message)
+	assert.NoError(t, err)
+
+	valid, err := pool.Verify(context.Background(), message, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = pool.Verify(context.Background(), "other", encoded)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestHashPoolConcurrency(t *testing.T) {
+	hasher := newBlockingHasher()
+	pool := crypto.NewHashPool(hasher, 2, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			encoded, err := pool.Hash(context.Background(), message)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "$blocking$"+message, encoded)
+		}()
+	}
+
+	<-hasher.started
+	<-hasher.started
+
+	// all calls are admitted before any hash finishes
+	assert.Eventually(t, func() bool {
+		return pool.Pending() == 6
+	}, time.Second, time.Millisecond)
+
+	close(hasher.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hasher.max))
+}
+
+func TestHashPoolQueueFull(t *testing.T) {
+	hasher := newBlockingHasher()
+	pool := crypto.NewHashPool(hasher, 1, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := pool.Hash(context.Background(), message)
+			assert.NoError(t, err)
+		}()
+	}
+
+	<-hasher.started
+
+	assert.Eventually(t, func() bool {
+		return pool.Pending() == 2
+	}, time.Second, time.Millisecond)
+
+	_, err := pool.Hash(context.Background(), message)
+	assert.Equal(t, crypto.ErrQueueFull, err)
+
+	close(hasher.release)
+	wg.Wait()
+
+	// slots are released right after results are returned
+	assert.Eventually(t, func() bool {
+		return pool.Pending() == 0
+	}, time.Second, time.Millisecond)
+
+	_, err = pool.Hash(context.Background(), message)
+	assert.NoError(t, err)
+}
+
+func TestHashPoolContextCanceled(t *testing.T) {
+	hasher := newBlockingHasher()
+	pool := crypto.NewHashPool(hasher, 1, 1)
+
+	running, cancelRunning := context.WithCancel(context.Background())
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := pool.Hash(running, message)
+		errs <- err
+	}()
+
+	<-hasher.started
+
+	waiting, cancelWaiting := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelWaiting()
+
+	_, err := pool.Hash(waiting, message)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	cancelRunning()
+	assert.Equal(t, context.Canceled, <-errs)
+
+	// canceled hash still holds its slot until computed
+	waiting, cancelWaiting = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelWaiting()
+
+	_, err = pool.Hash(waiting, message)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	close(hasher.release)
+
+	assert.Eventually(t, func() bool {
+		_, err := pool.Hash(context.Background(), message)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = pool.Hash(running, message)
+	assert.Equal(t, context.Canceled, err)
+}
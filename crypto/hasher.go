@@ -0,0 +1,169 @@
+package crypto
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidHash error
+var ErrInvalidHash = errors.New("invalid hash")
+
+// ErrUnknownHasher error
+var ErrUnknownHasher = errors.New("unknown password hasher")
+
+// PasswordHasher for password hashing and verification using PHC string format
+type PasswordHasher interface {
+	// ID returns PHC identifier, argon2id, scrypt, 2a...
+	ID() string
+	// Encode returns PHC string for given plain
+	Encode(plain string) (string, error)
+	// Verify returns whether plain matches encoded hash
+	Verify(plain, encoded string) (bool, error)
+	// NeedsRehash returns whether encoded hash was produced with different (outdated) params
+	NeedsRehash(encoded string) bool
+}
+
+// Registry of password hashers
+// New hashes are encoded with Default, verification is dispatched by encoded hash identifier
+type Registry struct {
+	Default PasswordHasher
+	hashers map[string]PasswordHasher
+}
+
+// NewRegistry will initialize Registry with def as default hasher and optional additional hashers
+func NewRegistry(def PasswordHasher, hashers ...PasswordHasher) *Registry {
+	registry := &Registry{
+		Default: def,
+		hashers: make(map[string]PasswordHasher),
+	}
+
+	registry.Register(def)
+	registry.Register(hashers...)
+
+	return registry
+}
+
+// Register hashers, existing hasher with the same ID will be replaced, nil hashers are ignored
+func (registry *Registry) Register(hashers ...PasswordHasher) {
+	if registry.hashers == nil {
+		registry.hashers = make(map[string]PasswordHasher)
+	}
+
+	for _, hasher := range hashers {
+		if hasher == nil {
+			continue
+		}
+
+		registry.hashers[hasher.ID()] = hasher
+	}
+}
+
+// ID implements PasswordHasher.ID, returns Default hasher ID or empty string if Default is not set
+func (registry *Registry) ID() string {
+	if registry.Default == nil {
+		return ""
+	}
+
+	return registry.Default.ID()
+}
+
+// Encode implements PasswordHasher.Encode using Default hasher
+func (registry *Registry) Encode(plain string) (string, error) {
+	if registry.Default == nil {
+		return "", ErrUnknownHasher
+	}
+
+	return registry.Default.Encode(plain)
+}
+
+// Verify implements PasswordHasher.Verify using hasher which produced encoded hash
+func (registry *Registry) Verify(plain, encoded string) (bool, error) {
+	hasher, err := registry.Identify(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	return hasher.Verify(plain, encoded)
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+// Hashes produced by non-default hasher or with outdated Default params need rehash
+func (registry *Registry) NeedsRehash(encoded string) bool {
+	hasher, err := registry.Identify(encoded)
+	if err != nil || registry.Default == nil {
+		return true
+	}
+
+	if hasher.ID() != registry.Default.ID() {
+		return true
+	}
+
+	return registry.Default.NeedsRehash(encoded)
+}
+
+// VerifyAndUpgrade will verify plain against encoded hash and, when valid and outdated,
+// return newEncoded hash made with Default hasher, newEncoded is empty if no upgrade is needed
+func (registry *Registry) VerifyAndUpgrade(plain, encoded string) (bool, string, error) {
+	return verifyAndUpgrade(registry, plain, encoded)
+}
+
+// Identify will return registered hasher which produced encoded hash
+func (registry *Registry) Identify(encoded string) (PasswordHasher, error) {
+	id, err := hashID(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, ok := registry.hashers[id]
+	if !ok {
+		return nil, ErrUnknownHasher
+	}
+
+	return hasher, nil
+}
+
+// hashID is helper function to detect hasher identifier of PHC or legacy encoded hash
+func hashID(encoded string) (string, error) {
+	if strings.HasPrefix(encoded, "$") {
+		vals := strings.SplitN(encoded[1:], "$", 2)
+		if len(vals) != 2 || vals[0] == "" {
+			return "", ErrInvalidHash
+		}
+
+		switch vals[0] {
+		case "2a", "2b", "2y":
+			return bcryptID, nil
+		default:
+			return vals[0], nil
+		}
+	}
+
+	// legacy formats produced by Argon2.Hash and SCrypt.Hash
+	switch strings.Count(encoded, "$") {
+	case 5:
+		return argon2ID, nil
+	case 4:
+		return scryptID, nil
+	default:
+		return "", ErrInvalidHash
+	}
+}
+
+// verifyAndUpgrade is helper function to verify plain and rehash it if hasher needs so
+func verifyAndUpgrade(hasher PasswordHasher, plain, encoded string) (bool, string, error) {
+	valid, err := hasher.Verify(plain, encoded)
+	if err != nil || !valid {
+		return false, "", err
+	}
+
+	if !hasher.NeedsRehash(encoded) {
+		return true, "", nil
+	}
+
+	newEncoded, err := hasher.Encode(plain)
+	if err != nil {
+		return true, "", err
+	}
+
+	return true, newEncoded, nil
+}
@@ -0,0 +1,161 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestRegistryEncode(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewSCrypt(), crypto.NewArgon2(), crypto.NewBCrypt())
+
+	encoded, err := registry.Encode(plainSCrypt)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$scrypt$"))
+	assert.Equal(t, "scrypt", registry.ID())
+}
+
+func TestRegistryMissingDefault(t *testing.T) {
+	registry := crypto.NewRegistry(nil, crypto.NewArgon2())
+
+	assert.Equal(t, "", registry.ID())
+
+	_, err := registry.Encode(plainArgon)
+	assert.Equal(t, crypto.ErrUnknownHasher, err)
+}
+
+func TestRegistryVerify(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewArgon2(), crypto.NewSCrypt(), crypto.NewBCrypt())
+
+	argonPHC, err := crypto.NewArgon2().Encode(plainArgon)
+	assert.NoError(t, err)
+
+	sCryptPHC, err := crypto.NewSCrypt().Encode(plainSCrypt)
+	assert.NoError(t, err)
+
+	hashes := []string{
+		argonPHC,
+		sCryptPHC,
+		hashedArgon,
+		hashedSCrypt,
+		hashedBCrypt,
+		"$2b$04$OoAzCwIxlW5KNiH2x8Y/jeKI/xYYkoL5hEjrSFPnre9j/T6hrvA2e",
+	}
+
+	for _, hashed := range hashes {
+		valid, err := registry.Verify("pwd-123", hashed)
+
+		assert.NoError(t, err, hashed)
+		assert.True(t, valid, hashed)
+
+		valid, err = registry.Verify("invalid", hashed)
+
+		assert.NoError(t, err, hashed)
+		assert.False(t, valid, hashed)
+	}
+}
+
+func TestRegistryIdentify(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewArgon2(), crypto.NewSCrypt(), crypto.NewBCrypt())
+
+	cases := map[string]string{
+		hashedArgon:  "argon2id",
+		hashedSCrypt: "scrypt",
+		hashedBCrypt: "2a",
+		"$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA": "argon2id",
+		"$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA":          "scrypt",
+	}
+
+	for hashed, id := range cases {
+		hasher, err := registry.Identify(hashed)
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, hasher.ID())
+	}
+}
+
+func TestRegistryUnknownHasher(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewBCrypt())
+
+	_, err := registry.Verify(plainArgon, hashedArgon)
+
+	assert.Equal(t, crypto.ErrUnknownHasher, err)
+
+	_, err = registry.Verify(plainArgon, "$pbkdf2-sha256$i=1000$c2FsdA$aGFzaA")
+
+	assert.Equal(t, crypto.ErrUnknownHasher, err)
+}
+
+func TestRegistryInvalidHash(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewArgon2(), crypto.NewSCrypt())
+
+	hashes := []string{
+		"",
+		"invalid",
+		"$",
+		"$argon2id$v=19$m=65536,t=3$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=3,p=2$c2FsdA",
+		"$argon2id$v=19$m=65536,t=0,p=2$c2FsdA$aGFzaA",
+		"19$65536$0$2$00$00",
+		"$scrypt$ln=x,r=8,p=1$c2FsdA$aGFzaA",
+		"$scrypt$ln=15,r=8,p=1$!!$aGFzaA",
+	}
+
+	for _, hashed := range hashes {
+		valid, err := registry.Verify(plainArgon, hashed)
+
+		assert.Error(t, err, hashed)
+		assert.False(t, valid, hashed)
+	}
+}
+
+func TestRegistryNeedsRehash(t *testing.T) {
+	argon := crypto.NewArgon2()
+	registry := crypto.NewRegistry(argon, crypto.NewSCrypt(), crypto.NewBCrypt())
+
+	current, err := registry.Encode(plainArgon)
+	assert.NoError(t, err)
+
+	assert.False(t, registry.NeedsRehash(current))
+	assert.True(t, registry.NeedsRehash(hashedArgon))
+	assert.True(t, registry.NeedsRehash(hashedSCrypt))
+	assert.True(t, registry.NeedsRehash(hashedBCrypt))
+	assert.True(t, registry.NeedsRehash("invalid"))
+
+	argon.Time = 4
+
+	assert.True(t, registry.NeedsRehash(current))
+}
+
+func TestRegistryVerifyAndUpgrade(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewArgon2(), crypto.NewSCrypt(), crypto.NewBCrypt())
+
+	for _, hashed := range []string{hashedArgon, hashedSCrypt, hashedBCrypt} {
+		valid, upgraded, err := registry.VerifyAndUpgrade("pwd-123", hashed)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+		assert.False(t, registry.NeedsRehash(upgraded))
+
+		valid, upgraded, err = registry.VerifyAndUpgrade("pwd-123", upgraded)
+
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.Empty(t, upgraded)
+	}
+}
+
+func TestRegistryVerifyAndUpgradeInvalidPlain(t *testing.T) {
+	registry := crypto.NewRegistry(crypto.NewArgon2(), crypto.NewSCrypt())
+
+	valid, upgraded, err := registry.VerifyAndUpgrade("invalid", hashedSCrypt)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Empty(t, upgraded)
+}
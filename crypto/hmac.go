@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"strings"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// hmacSeparator separates key id and mac in HMAC signature
+const hmacSeparator = "."
+
+// HMAC message authentication with multiple keys
+// Payload is always signed with KeyID key, signature is: key id.base64 URL encoded mac
+type HMAC struct {
+	KeyID string
+	Keys  map[string][]byte
+	// Hash function, sha256.New if not set
+	Hash func() hash.Hash
+}
+
+// NewHMACSHA256 will initialize HMAC-SHA256 with single key
+func NewHMACSHA256(keyID string, key []byte) *HMAC {
+	return &HMAC{
+		KeyID: keyID,
+		Keys:  map[string][]byte{keyID: key},
+		Hash:  sha256.New,
+	}
+}
+
+// NewHMACSHA512 will initialize HMAC-SHA512 with single key
+func NewHMACSHA512(keyID string, key []byte) *HMAC {
+	return &HMAC{
+		KeyID: keyID,
+		Keys:  map[string][]byte{keyID: key},
+		Hash:  sha512.New,
+	}
+}
+
+// Sign will return signature of payload made with KeyID key
+func (h *HMAC) Sign(payload []byte) (string, error) {
+	if h.KeyID == "" || strings.Contains(h.KeyID, hmacSeparator) {
+		return "", errors.New("key id must not be empty or contain " + hmacSeparator)
+	}
+
+	mac, err := h.mac(h.KeyID, payload)
+	if err != nil {
+		return "", err
+	}
+
+	return h.KeyID + hmacSeparator + str.Base64URLEncode(string(mac)), nil
+}
+
+// Verify will verify signature of payload with key it was signed with, in constant time
+// Returns ErrInvalidSignature if signature is not valid
+func (h *HMAC) Verify(payload []byte, signature string) error {
+	i := strings.LastIndex(signature, hmacSeparator)
+	if i < 0 {
+		return ErrInvalidSignature
+	}
+
+	mac, err := str.Base64URLDecode(signature[i+1:])
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected, err := h.mac(signature[:i], payload)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(expected, []byte(mac)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// HMACKeyID will return id of key signature was signed with
+func HMACKeyID(signature string) (string, error) {
+	i := strings.LastIndex(signature, hmacSeparator)
+	if i < 0 {
+		return "", ErrInvalidSignature
+	}
+
+	return signature[:i], nil
+}
+
+// mac is helper function to calculate mac of payload using keyID key
+func (h *HMAC) mac(keyID string, payload []byte) ([]byte, error) {
+	key, ok := h.Keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if len(key) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	hashFunc := h.Hash
+	if hashFunc == nil {
+		hashFunc = sha256.New
+	}
+
+	mac := hmac.New(hashFunc, key)
+	mac.Write(payload)
+
+	return mac.Sum(nil), nil
+}
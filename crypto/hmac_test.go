@@ -0,0 +1,77 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+	"github.com/semirm-dev/godev/str"
+)
+
+// RFC 4231, test case 2
+var (
+	hmacKey     = []byte("Jefe")
+	hmacMessage = []byte("what do ya want for nothing?")
+	hmacSHA256  = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	hmacSHA512  = "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea250554" +
+		"9758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737"
+)
+
+func TestHMACKnownAnswer(t *testing.T) {
+	cases := map[string]*crypto.HMAC{
+		hmacSHA256: crypto.NewHMACSHA256("k1", hmacKey),
+		hmacSHA512: crypto.NewHMACSHA512("k1", hmacKey),
+	}
+
+	for expected, h := range cases {
+		signature, err := h.Sign(hmacMessage)
+		assert.NoError(t, err)
+
+		mac, err := hex.DecodeString(expected)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "k1."+str.Base64URLEncode(string(mac)), signature)
+		assert.NoError(t, h.Verify(hmacMessage, signature))
+	}
+}
+
+func TestHMACVerify(t *testing.T) {
+	old := crypto.NewHMACSHA256("v1", []byte("old-key"))
+
+	oldSignature, err := old.Sign([]byte(message))
+	assert.NoError(t, err)
+
+	h := &crypto.HMAC{
+		KeyID: "v2",
+		Keys: map[string][]byte{
+			"v1": []byte("old-key"),
+			"v2": []byte("new-key"),
+		},
+	}
+
+	signature, err := h.Sign([]byte(message))
+	assert.NoError(t, err)
+
+	keyID, err := crypto.HMACKeyID(signature)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", keyID)
+
+	assert.NoError(t, h.Verify([]byte(message), signature))
+	assert.NoError(t, h.Verify([]byte(message), oldSignature))
+
+	assert.Equal(t, crypto.ErrInvalidSignature, h.Verify([]byte("other"), signature))
+	assert.Equal(t, crypto.ErrInvalidSignature, h.Verify([]byte(message), "invalid"))
+	assert.Equal(t, crypto.ErrUnknownKey, h.Verify([]byte(message), "v3"+signature[2:]))
+	assert.Equal(t, crypto.ErrUnknownKey, old.Verify([]byte(message), signature))
+}
+
+func TestHMACInvalidKeyID(t *testing.T) {
+	_, err := crypto.NewHMACSHA256("v.1", hmacKey).Sign(hmacMessage)
+	assert.Error(t, err)
+
+	_, err = crypto.NewHMACSHA256("", hmacKey).Sign(hmacMessage)
+	assert.Error(t, err)
+}
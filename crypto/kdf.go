@@ -0,0 +1,140 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// hkdfID is identifier for HKDF-SHA256
+	hkdfID = "hkdf-sha256"
+	// hkdfMaxKeyLen is max HKDF-SHA256 output length, 255 blocks of 32 bytes
+	hkdfMaxKeyLen = 255 * sha256.Size
+
+	// kdfMaxMemory is max memory in KiB of KDF taken from untrusted params, 1 GiB
+	kdfMaxMemory = 1 << 20
+	// kdfMaxArgonTime is max Argon2 iterations of KDF taken from untrusted params
+	kdfMaxArgonTime = 10
+	// kdfMaxArgonThreads is max Argon2 threads of KDF taken from untrusted params
+	kdfMaxArgonThreads = 16
+	// kdfMaxSCryptLn is max log2 of SCrypt N of KDF taken from untrusted params
+	kdfMaxSCryptLn = 22
+	// kdfMaxSCryptRP is max SCrypt r*p of KDF taken from untrusted params
+	kdfMaxSCryptRP = 64
+)
+
+var (
+	// ErrInvalidKeyLen error
+	ErrInvalidKeyLen = errors.New("invalid derived key length")
+	// ErrKDFParamsLimit error, returned for KDF params exceeding limits of untrusted input
+	ErrKDFParamsLimit = errors.New("kdf params exceed limits")
+)
+
+// KDF derives keys from secret and salt
+// Argon2 and SCrypt are suited for passphrases, HKDF only for high-entropy secrets
+type KDF interface {
+	// ID returns KDF identifier, argon2id, scrypt, hkdf-sha256
+	ID() string
+	// Params returns KDF params in PHC format, m=65536,t=3,p=2
+	Params() string
+	// Key returns derived key of given length
+	Key(secret, salt []byte, keyLen int) ([]byte, error)
+}
+
+// HKDF key derivation using HKDF-SHA256
+type HKDF struct{}
+
+// DeriveKey will derive key of keyLen from passphrase and salt using kdf
+// keyLen of 16, 24 or 32 can be used as AES key
+func DeriveKey(kdf KDF, passphrase string, salt []byte, keyLen int) ([]byte, error) {
+	if kdf == nil {
+		return nil, errors.New("missing KDF")
+	}
+
+	if keyLen <= 0 {
+		return nil, ErrInvalidKeyLen
+	}
+
+	return kdf.Key([]byte(passphrase), salt, keyLen)
+}
+
+// ID implements KDF.ID
+func (HKDF) ID() string {
+	return hkdfID
+}
+
+// Params implements KDF.Params, HKDF has no params
+func (HKDF) Params() string {
+	return ""
+}
+
+// Key implements KDF.Key, keyLen is limited to 255*32 bytes
+func (HKDF) Key(secret, salt []byte, keyLen int) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	if keyLen <= 0 || keyLen > hkdfMaxKeyLen {
+		return nil, ErrInvalidKeyLen
+	}
+
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, nil), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// kdfFromParams is helper function to create KDF from its id and PHC params
+// Params come from untrusted input, so KDFs exceeding limits are rejected before any key is derived
+func kdfFromParams(id, params string) (KDF, error) {
+	values, err := parsePHCParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	phc := &phcHash{
+		ID:     id,
+		Params: values,
+	}
+
+	switch id {
+	case argon2ID:
+		argon, err := argonFromParams(phc)
+		if err != nil {
+			return nil, err
+		}
+
+		if argon.Memory > kdfMaxMemory || argon.Time > kdfMaxArgonTime || argon.Threads > kdfMaxArgonThreads {
+			return nil, ErrKDFParamsLimit
+		}
+
+		return argon, nil
+	case scryptID:
+		if ln, err := phc.param("ln"); err == nil && ln > kdfMaxSCryptLn {
+			return nil, ErrKDFParamsLimit
+		}
+
+		sCrypt, err := sCryptFromParams(phc)
+		if err != nil {
+			return nil, err
+		}
+
+		if sCrypt.R <= 0 || sCrypt.P <= 0 ||
+			sCrypt.R > kdfMaxSCryptRP || sCrypt.P > kdfMaxSCryptRP ||
+			sCrypt.R*sCrypt.P > kdfMaxSCryptRP ||
+			int64(sCrypt.N)*int64(sCrypt.R)/8 > kdfMaxMemory {
+			return nil, ErrKDFParamsLimit
+		}
+
+		return sCrypt, nil
+	case hkdfID:
+		return HKDF{}, nil
+	default:
+		return nil, ErrUnknownHasher
+	}
+}
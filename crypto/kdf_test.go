@@ -0,0 +1,76 @@
+package crypto_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestDeriveKey(t *testing.T) {
+	kdfs := []crypto.KDF{
+		crypto.NewArgon2(),
+		crypto.NewSCrypt(),
+		crypto.HKDF{},
+	}
+
+	salt := []byte("0123456789abcdef")
+
+	for _, kdf := range kdfs {
+		key, err := crypto.DeriveKey(kdf, "passphrase", salt, 32)
+
+		assert.NoError(t, err, kdf.ID())
+		assert.Len(t, key, 32, kdf.ID())
+
+		again, err := crypto.DeriveKey(kdf, "passphrase", salt, 32)
+
+		assert.NoError(t, err, kdf.ID())
+		assert.Equal(t, key, again, kdf.ID())
+
+		other, err := crypto.DeriveKey(kdf, "passphrase", []byte("fedcba9876543210"), 32)
+
+		assert.NoError(t, err, kdf.ID())
+		assert.NotEqual(t, key, other, kdf.ID())
+
+		_, err = crypto.DeriveKey(kdf, "", salt, 32)
+
+		assert.Equal(t, crypto.ErrMissingSecret, err, kdf.ID())
+
+		for _, keyLen := range []int{0, -1} {
+			_, err = crypto.DeriveKey(kdf, "passphrase", salt, keyLen)
+			assert.Equal(t, crypto.ErrInvalidKeyLen, err, kdf.ID())
+
+			_, err = kdf.Key([]byte("passphrase"), salt, keyLen)
+			assert.Equal(t, crypto.ErrInvalidKeyLen, err, kdf.ID())
+		}
+	}
+}
+
+// RFC 5869, test case 3
+func TestHKDFKnownAnswer(t *testing.T) {
+	ikm := decodeHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+
+	key, err := crypto.HKDF{}.Key(ikm, nil, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8", hex.EncodeToString(key))
+}
+
+func TestHKDFMaxKeyLen(t *testing.T) {
+	key, err := crypto.HKDF{}.Key([]byte("secret"), nil, 255*32)
+
+	assert.NoError(t, err)
+	assert.Len(t, key, 255*32)
+
+	_, err = crypto.HKDF{}.Key([]byte("secret"), nil, 255*32+1)
+
+	assert.Equal(t, crypto.ErrInvalidKeyLen, err)
+}
+
+func TestKDFParams(t *testing.T) {
+	assert.Equal(t, "m=65536,t=3,p=2", crypto.NewArgon2().Params())
+	assert.Equal(t, "ln=15,r=8,p=1", crypto.NewSCrypt().Params())
+	assert.Equal(t, "", crypto.HKDF{}.Params())
+}
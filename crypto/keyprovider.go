@@ -0,0 +1,146 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/semirm-dev/godev/env"
+)
+
+// ErrUnknownKey error
+var ErrUnknownKey = errors.New("unknown key id")
+
+// KeyProvider wraps and unwraps data keys with key encryption keys (KEK)
+type KeyProvider interface {
+	// WrapKey returns id of key encryption key and data key encrypted with it
+	WrapKey(dataKey []byte) (string, []byte, error)
+	// UnwrapKey returns data key encrypted with key encryption key of given id
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// StaticKeyProvider holds key encryption keys locally and wraps data keys using AES GCM
+type StaticKeyProvider struct {
+	Primary string
+	Keys    map[string][]byte
+}
+
+// keyringFile is json file format of local keyring
+// {"primary": "2", "keys": {"1": "base64 key", "2": "base64 key"}}
+type keyringFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewMemoryKeyProvider will initialize StaticKeyProvider with one randomly generated 256bit key
+// Keys are lost once process exits, useful for tests
+func NewMemoryKeyProvider() (*StaticKeyProvider, error) {
+	key, err := GenerateSalt(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticKeyProvider{
+		Primary: "memory",
+		Keys: map[string][]byte{
+			"memory": key,
+		},
+	}, nil
+}
+
+// NewFileKeyProvider will initialize StaticKeyProvider from json keyring file at path
+func NewFileKeyProvider(path string) (*StaticKeyProvider, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := &keyringFile{}
+	if err := json.Unmarshal(content, keyring); err != nil {
+		return nil, err
+	}
+
+	provider := &StaticKeyProvider{
+		Primary: keyring.Primary,
+		Keys:    make(map[string][]byte),
+	}
+
+	for id, encoded := range keyring.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %v", id, err)
+		}
+		provider.Keys[id] = key
+	}
+
+	if _, ok := provider.Keys[provider.Primary]; !ok {
+		return nil, errors.New("primary key missing in keyring")
+	}
+
+	return provider, nil
+}
+
+// NewEnvKeyProvider will initialize StaticKeyProvider with base64 encoded master key
+// from env variable name, env variable name is used as key id
+func NewEnvKeyProvider(name string) (*StaticKeyProvider, error) {
+	encoded := strings.TrimSpace(env.Get(name, ""))
+	if encoded == "" {
+		return nil, ErrMissingSecret
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StaticKeyProvider{
+		Primary: name,
+		Keys: map[string][]byte{
+			name: key,
+		},
+	}, nil
+}
+
+// WrapKey implements KeyProvider.WrapKey using Primary key
+func (provider *StaticKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	kek, ok := provider.Keys[provider.Primary]
+	if !ok {
+		return "", nil, ErrUnknownKey
+	}
+
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce, err := GenerateSalt(gcm.NonceSize())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return provider.Primary, gcm.Seal(nonce, nonce, dataKey, []byte(provider.Primary)), nil
+}
+
+// UnwrapKey implements KeyProvider.UnwrapKey
+func (provider *StaticKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := provider.Keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, ErrEncryptedTooShort
+	}
+
+	nonce, encrypted := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, encrypted, []byte(keyID))
+}
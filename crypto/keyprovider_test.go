@@ -0,0 +1,97 @@
+package crypto_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestFileKeyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keyring.json")
+	oldKey := base64.StdEncoding.EncodeToString([]byte(secret))
+	newKey := base64.StdEncoding.EncodeToString([]byte("D*G-KaPdSgVkYp3sD*G-KaPdSgVkYp3s"))
+
+	err = ioutil.WriteFile(path, []byte(`{"primary": "1", "keys": {"1": "`+oldKey+`"}}`), 0600)
+	assert.NoError(t, err)
+
+	provider, err := crypto.NewFileKeyProvider(path)
+	assert.NoError(t, err)
+
+	encrypted, _, _, err := (&crypto.Envelope{KeyProvider: provider}).Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	// rotate primary key, old key is kept for decryption
+	err = ioutil.WriteFile(path, []byte(`{"primary": "2", "keys": {"1": "`+oldKey+`", "2": "`+newKey+`"}}`), 0600)
+	assert.NoError(t, err)
+
+	provider, err = crypto.NewFileKeyProvider(path)
+	assert.NoError(t, err)
+
+	envelope := &crypto.Envelope{KeyProvider: provider}
+
+	decrypted, err := envelope.Decrypt(encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	rotated, _, _, err := envelope.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	keyID, err := crypto.EnvelopeKeyID(rotated)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2", keyID)
+}
+
+func TestFileKeyProviderMissingPrimary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keyring.json")
+
+	err = ioutil.WriteFile(path, []byte(`{"primary": "2", "keys": {}}`), 0600)
+	assert.NoError(t, err)
+
+	_, err = crypto.NewFileKeyProvider(path)
+
+	assert.Error(t, err)
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	os.Setenv("TEST_MASTER_KEY", base64.StdEncoding.EncodeToString([]byte(secret)))
+	defer os.Unsetenv("TEST_MASTER_KEY")
+
+	provider, err := crypto.NewEnvKeyProvider("TEST_MASTER_KEY")
+	assert.NoError(t, err)
+
+	keyID, wrapped, err := provider.WrapKey([]byte("data-key"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST_MASTER_KEY", keyID)
+
+	dataKey, err := provider.UnwrapKey(keyID, wrapped)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data-key"), dataKey)
+
+	_, err = provider.UnwrapKey("unknown", wrapped)
+
+	assert.Equal(t, crypto.ErrUnknownKey, err)
+}
+
+func TestEnvKeyProviderMissing(t *testing.T) {
+	_, err := crypto.NewEnvKeyProvider("NOT_EXISTS_MASTER_KEY")
+
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+}
@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+// Keyring crypter holds multiple versioned crypters (keys)
+// Payload is always encrypted with Primary key and prefixed with its id:
+// key id length (1 byte) | key id | encrypted
+type Keyring struct {
+	Primary string
+	Keys    map[string]Crypter
+}
+
+// ErrInvalidKeyring error
+var ErrInvalidKeyring = errors.New("invalid keyring encrypted input")
+
+// Encrypt payload with Primary key
+func (keyring *Keyring) Encrypt(payload []byte) (string, string, string, error) {
+	crypter, err := keyring.crypter(keyring.Primary)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if len(keyring.Primary) == 0 || len(keyring.Primary) > 0xff {
+		return "", "", "", errors.New("key id must be 1 to 255 bytes long")
+	}
+
+	enc, _, _, err := crypter.Encrypt(payload)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	encrypted := string([]byte{byte(len(keyring.Primary))}) + keyring.Primary + enc
+
+	return encrypted, hex.EncodeToString([]byte(encrypted)), str.Base64URLEncode(encrypted), nil
+}
+
+// Decrypt keyring encrypted input with key it was encrypted with
+func (keyring *Keyring) Decrypt(encrypted string) (string, error) {
+	keyID, enc, err := splitKeyID(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	crypter, err := keyring.crypter(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	return crypter.Decrypt(enc)
+}
+
+// Reencrypt will decrypt each encrypted input and encrypt it again with Primary key
+// Inputs already encrypted with Primary key are returned as they are
+func (keyring *Keyring) Reencrypt(encrypted ...string) ([]string, error) {
+	reencrypted := make([]string, 0, len(encrypted))
+
+	for _, enc := range encrypted {
+		keyID, err := KeyringKeyID(enc)
+		if err != nil {
+			return nil, err
+		}
+
+		if keyID == keyring.Primary {
+			reencrypted = append(reencrypted, enc)
+			continue
+		}
+
+		decrypted, err := keyring.Decrypt(enc)
+		if err != nil {
+			return nil, err
+		}
+
+		enc, _, _, err = keyring.Encrypt([]byte(decrypted))
+		if err != nil {
+			return nil, err
+		}
+
+		reencrypted = append(reencrypted, enc)
+	}
+
+	return reencrypted, nil
+}
+
+// KeyringKeyID will return id of key used for keyring encrypted input
+func KeyringKeyID(encrypted string) (string, error) {
+	keyID, _, err := splitKeyID(encrypted)
+
+	return keyID, err
+}
+
+// crypter is helper function to get crypter for given key id
+func (keyring *Keyring) crypter(keyID string) (Crypter, error) {
+	crypter, ok := keyring.Keys[keyID]
+	if !ok || crypter == nil {
+		return nil, ErrUnknownKey
+	}
+
+	return crypter, nil
+}
+
+// splitKeyID is helper function to split keyring encrypted input into key id and encrypted part
+func splitKeyID(encrypted string) (string, string, error) {
+	if len(encrypted) == 0 {
+		return "", "", ErrInvalidKeyring
+	}
+
+	keyIDEnd := 1 + int(encrypted[0])
+	if keyIDEnd == 1 || len(encrypted) < keyIDEnd {
+		return "", "", ErrInvalidKeyring
+	}
+
+	return encrypted[1:keyIDEnd], encrypted[keyIDEnd:], nil
+}
@@ -0,0 +1,109 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+var (
+	keyringV1 = &crypto.CBC{
+		Secret: secret,
+		IV:     iv,
+	}
+	keyringV2 = &crypto.GCM{
+		Secret: "D*G-KaPdSgVkYp3sD*G-KaPdSgVkYp3s",
+	}
+)
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	cipher := &crypto.Cipher{
+		Crypter: &crypto.Keyring{
+			Primary: "v2",
+			Keys: map[string]crypto.Crypter{
+				"v1": keyringV1,
+				"v2": keyringV2,
+			},
+		},
+	}
+
+	err := cipher.Encrypt([]byte(message))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cipher.Hex)
+	assert.NotEmpty(t, cipher.Base64)
+
+	keyID, err := crypto.KeyringKeyID(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", keyID)
+
+	err = cipher.Decrypt(cipher.Encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, cipher.Decrypted)
+}
+
+func TestKeyringRotation(t *testing.T) {
+	keyring := &crypto.Keyring{
+		Primary: "v1",
+		Keys: map[string]crypto.Crypter{
+			"v1": keyringV1,
+		},
+	}
+
+	old, _, _, err := keyring.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	keyring.Keys["v2"] = keyringV2
+	keyring.Primary = "v2"
+
+	decrypted, err := keyring.Decrypt(old)
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	current, _, _, err := keyring.Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	reencrypted, err := keyring.Reencrypt(old, current)
+
+	assert.NoError(t, err)
+	assert.Len(t, reencrypted, 2)
+	assert.Equal(t, current, reencrypted[1])
+
+	for _, enc := range reencrypted {
+		keyID, err := crypto.KeyringKeyID(enc)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", keyID)
+
+		decrypted, err := keyring.Decrypt(enc)
+
+		assert.NoError(t, err)
+		assert.Equal(t, message, decrypted)
+	}
+}
+
+func TestKeyringUnknownKey(t *testing.T) {
+	keyring := &crypto.Keyring{
+		Primary: "v1",
+		Keys: map[string]crypto.Crypter{
+			"v1": keyringV1,
+		},
+	}
+
+	_, _, _, err := (&crypto.Keyring{Primary: "v3", Keys: keyring.Keys}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrUnknownKey, err)
+
+	_, err = keyring.Decrypt("\x02v3encrypted")
+	assert.Equal(t, crypto.ErrUnknownKey, err)
+
+	_, err = keyring.Decrypt("")
+	assert.Equal(t, crypto.ErrInvalidKeyring, err)
+
+	_, err = keyring.Decrypt("\x09v1")
+	assert.Equal(t, crypto.ErrInvalidKeyring, err)
+}
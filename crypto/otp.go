@@ -0,0 +1,321 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OTPSHA1 is HMAC-SHA1 one-time password algorithm, supported by most authenticator apps
+	OTPSHA1 = "SHA1"
+	// OTPSHA256 is HMAC-SHA256 one-time password algorithm
+	OTPSHA256 = "SHA256"
+	// OTPSHA512 is HMAC-SHA512 one-time password algorithm
+	OTPSHA512 = "SHA512"
+)
+
+// otpSecretSize is default secret size, 160 bits as recommended by RFC 4226
+const otpSecretSize = 20
+
+// otpEncoding is base32 encoding of secrets in otpauth URI
+var otpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	// ErrInvalidOTPDigits error
+	ErrInvalidOTPDigits = errors.New("one-time password digits must be between 6 and 8")
+	// ErrUnknownOTPAlgorithm error
+	ErrUnknownOTPAlgorithm = errors.New("unknown one-time password algorithm")
+)
+
+// HOTP is HMAC-based one-time password (RFC 4226)
+type HOTP struct {
+	Secret []byte
+	// Digits of generated code, 6 if not set
+	Digits int
+	// Algorithm, OTPSHA1 if not set
+	Algorithm string
+}
+
+// TOTP is time-based one-time password (RFC 6238)
+type TOTP struct {
+	HOTP
+	// Period of single code, 30 seconds if not set
+	Period time.Duration
+	// Skew is number of periods before and after current one accepted in validation
+	Skew int
+	// ReplayGuard rejects already used codes, optional
+	ReplayGuard OTPReplayGuard
+	// Now returns current time, time.Now if not set
+	Now func() time.Time
+}
+
+// OTPReplayGuard prevents the same one-time password being used twice
+// Implementations are usually per account and persisted, e.g. last used step in database
+type OTPReplayGuard interface {
+	// Use marks counter (time step) as used, returns false if it was already used
+	Use(counter uint64) (bool, error)
+}
+
+// LastStepGuard is in-memory OTPReplayGuard accepting only counters greater than the last used one
+type LastStepGuard struct {
+	mu   sync.Mutex
+	last uint64
+	used bool
+}
+
+// GenerateOTPSecret will generate random 160 bit one-time password secret
+func GenerateOTPSecret() ([]byte, error) {
+	return GenerateSalt(otpSecretSize)
+}
+
+// EncodeOTPSecret will encode secret as unpadded base32, as used by authenticator apps
+func EncodeOTPSecret(secret []byte) string {
+	return otpEncoding.EncodeToString(secret)
+}
+
+// DecodeOTPSecret will decode base32 secret, padding, spaces and lowercase are accepted
+func DecodeOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+
+	return otpEncoding.DecodeString(strings.TrimRight(secret, "="))
+}
+
+// NewTOTP will initialize TOTP with default params: 6 digits, SHA1, 30 seconds period and 1 period skew
+func NewTOTP(secret []byte) *TOTP {
+	return &TOTP{
+		HOTP: HOTP{
+			Secret:    secret,
+			Digits:    6,
+			Algorithm: OTPSHA1,
+		},
+		Period: 30 * time.Second,
+		Skew:   1,
+	}
+}
+
+// Generate will return code for counter
+func (hotp *HOTP) Generate(counter uint64) (string, error) {
+	if len(hotp.Secret) == 0 {
+		return "", ErrMissingSecret
+	}
+
+	digits := hotp.digits()
+	if digits < 6 || digits > 8 {
+		return "", ErrInvalidOTPDigits
+	}
+
+	hashFunc, err := otpHash(hotp.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(hashFunc, hotp.Secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, code%mod), nil
+}
+
+// Validate will check code against counter and up to lookAhead following counters
+// Returns counter matched code, next code must be validated against counter + 1
+func (hotp *HOTP) Validate(code string, counter uint64, lookAhead int) (uint64, bool, error) {
+	for i := 0; i <= lookAhead; i++ {
+		expected, err := hotp.Generate(counter + uint64(i))
+		if err != nil {
+			return 0, false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + uint64(i), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// URI will return otpauth:// provisioning URI, usually shown as QR code
+func (hotp *HOTP) URI(issuer, account string, counter uint64) string {
+	params := hotp.uriParams(issuer)
+	params.Set("counter", strconv.FormatUint(counter, 10))
+
+	return otpURI("hotp", issuer, account, params)
+}
+
+// Generate will return code for current time
+func (totp *TOTP) Generate() (string, error) {
+	return totp.GenerateAt(totp.now())
+}
+
+// GenerateAt will return code for given time
+func (totp *TOTP) GenerateAt(t time.Time) (string, error) {
+	return totp.HOTP.Generate(totp.Counter(t))
+}
+
+// Validate will check code for current time within Skew, and mark its time step as used in ReplayGuard
+func (totp *TOTP) Validate(code string) (bool, error) {
+	return totp.ValidateAt(code, totp.now())
+}
+
+// ValidateAt will check code for given time within Skew, and mark its time step as used in ReplayGuard
+func (totp *TOTP) ValidateAt(code string, t time.Time) (bool, error) {
+	counter := totp.Counter(t)
+
+	for i := -totp.Skew; i <= totp.Skew; i++ {
+		if i < 0 && uint64(-i) > counter {
+			continue
+		}
+
+		step := uint64(int64(counter) + int64(i))
+
+		expected, err := totp.HOTP.Generate(step)
+		if err != nil {
+			return false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		if totp.ReplayGuard == nil {
+			return true, nil
+		}
+
+		return totp.ReplayGuard.Use(step)
+	}
+
+	return false, nil
+}
+
+// Counter will return time step of given time
+func (totp *TOTP) Counter(t time.Time) uint64 {
+	if t.Unix() < 0 {
+		return 0
+	}
+
+	return uint64(t.Unix()) / uint64(totp.period()/time.Second)
+}
+
+// URI will return otpauth:// provisioning URI, usually shown as QR code
+func (totp *TOTP) URI(issuer, account string) string {
+	params := totp.uriParams(issuer)
+	params.Set("period", strconv.Itoa(int(totp.period()/time.Second)))
+
+	return otpURI("totp", issuer, account, params)
+}
+
+// Use implements OTPReplayGuard.Use
+func (guard *LastStepGuard) Use(counter uint64) (bool, error) {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	if guard.used && counter <= guard.last {
+		return false, nil
+	}
+
+	guard.last = counter
+	guard.used = true
+
+	return true, nil
+}
+
+// digits is helper function to get digits or default
+func (hotp *HOTP) digits() int {
+	if hotp.Digits == 0 {
+		return 6
+	}
+
+	return hotp.Digits
+}
+
+// uriParams is helper function to get common otpauth URI params
+func (hotp *HOTP) uriParams(issuer string) url.Values {
+	algorithm := hotp.Algorithm
+	if algorithm == "" {
+		algorithm = OTPSHA1
+	}
+
+	params := url.Values{}
+	params.Set("secret", EncodeOTPSecret(hotp.Secret))
+	params.Set("algorithm", algorithm)
+	params.Set("digits", strconv.Itoa(hotp.digits()))
+
+	if issuer != "" {
+		params.Set("issuer", issuer)
+	}
+
+	return params
+}
+
+// period is helper function to get period or default
+func (totp *TOTP) period() time.Duration {
+	if totp.Period < time.Second {
+		return 30 * time.Second
+	}
+
+	return totp.Period
+}
+
+// now is helper function to get current time
+func (totp *TOTP) now() time.Time {
+	if totp.Now != nil {
+		return totp.Now()
+	}
+
+	return time.Now()
+}
+
+// otpHash is helper function to get hash function of algorithm
+func otpHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", OTPSHA1:
+		return sha1.New, nil
+	case OTPSHA256:
+		return sha256.New, nil
+	case OTPSHA512:
+		return sha512.New, nil
+	default:
+		return nil, ErrUnknownOTPAlgorithm
+	}
+}
+
+// otpURI is helper function to build otpauth URI, label is issuer:account
+func otpURI(otpType, issuer, account string, params url.Values) string {
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     otpType,
+		Path:     "/" + label,
+		RawQuery: strings.Replace(params.Encode(), "+", "%20", -1),
+	}
+
+	return u.String()
+}
@@ -0,0 +1,196 @@
+package crypto_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+// RFC 4226, appendix D
+var hotpCodes = []string{
+	"755224", "287082", "359152", "969429", "338314",
+	"254676", "287922", "162583", "399871", "520489",
+}
+
+// RFC 6238, appendix B
+var totpCodes = []struct {
+	unix   int64
+	sha1   string
+	sha256 string
+	sha512 string
+}{
+	{59, "94287082", "46119246", "90693936"},
+	{1111111109, "07081804", "68084774", "25091201"},
+	{1111111111, "14050471", "67062674", "99943326"},
+	{1234567890, "89005924", "91819424", "93441116"},
+	{2000000000, "69279037", "90698825", "38618901"},
+	{20000000000, "65353130", "77737706", "47863826"},
+}
+
+var (
+	otpSecret20 = []byte("12345678901234567890")
+	otpSecret32 = []byte("12345678901234567890123456789012")
+	otpSecret64 = []byte("1234567890123456789012345678901234567890123456789012345678901234")
+)
+
+func TestHOTPKnownAnswer(t *testing.T) {
+	hotp := &crypto.HOTP{Secret: otpSecret20}
+
+	for counter, expected := range hotpCodes {
+		code, err := hotp.Generate(uint64(counter))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func TestHOTPValidate(t *testing.T) {
+	hotp := &crypto.HOTP{Secret: otpSecret20}
+
+	counter, valid, err := hotp.Validate(hotpCodes[3], 1, 2)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, uint64(3), counter)
+
+	_, valid, err = hotp.Validate(hotpCodes[4], 1, 2)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestTOTPKnownAnswer(t *testing.T) {
+	secrets := map[string][]byte{
+		crypto.OTPSHA1:   otpSecret20,
+		crypto.OTPSHA256: otpSecret32,
+		crypto.OTPSHA512: otpSecret64,
+	}
+
+	for _, c := range totpCodes {
+		at := time.Unix(c.unix, 0)
+
+		for algorithm, expected := range map[string]string{
+			crypto.OTPSHA1:   c.sha1,
+			crypto.OTPSHA256: c.sha256,
+			crypto.OTPSHA512: c.sha512,
+		} {
+			totp := crypto.NewTOTP(secrets[algorithm])
+			totp.Algorithm = algorithm
+			totp.Digits = 8
+
+			code, err := totp.GenerateAt(at)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, code, "%s at %d", algorithm, c.unix)
+
+			valid, err := totp.ValidateAt(expected, at)
+
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		}
+	}
+}
+
+func TestTOTPSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	totp := crypto.NewTOTP(otpSecret20)
+	totp.Now = func() time.Time {
+		return now
+	}
+
+	previous, err := totp.GenerateAt(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+
+	tooOld, err := totp.GenerateAt(now.Add(-60 * time.Second))
+	assert.NoError(t, err)
+
+	valid, err := totp.Validate(previous)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = totp.Validate(tooOld)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	totp.Skew = 0
+
+	valid, err = totp.Validate(previous)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestTOTPReplayGuard(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	totp := crypto.NewTOTP(otpSecret20)
+	totp.ReplayGuard = &crypto.LastStepGuard{}
+
+	code, err := totp.GenerateAt(now)
+	assert.NoError(t, err)
+
+	valid, err := totp.ValidateAt(code, now)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = totp.ValidateAt(code, now)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// code from previous step is rejected once later step was used
+	previous, err := totp.GenerateAt(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+
+	valid, err = totp.ValidateAt(previous, now)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestOTPInvalidParams(t *testing.T) {
+	_, err := (&crypto.HOTP{Secret: otpSecret20, Digits: 10}).Generate(0)
+	assert.Equal(t, crypto.ErrInvalidOTPDigits, err)
+
+	_, err = (&crypto.HOTP{Secret: otpSecret20, Algorithm: "MD5"}).Generate(0)
+	assert.Equal(t, crypto.ErrUnknownOTPAlgorithm, err)
+
+	_, err = (&crypto.HOTP{}).Generate(0)
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+}
+
+func TestOTPSecret(t *testing.T) {
+	secret, err := crypto.GenerateOTPSecret()
+
+	assert.NoError(t, err)
+	assert.Len(t, secret, 20)
+
+	encoded := crypto.EncodeOTPSecret(otpSecret20)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", encoded)
+
+	decoded, err := crypto.DecodeOTPSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+
+	assert.NoError(t, err)
+	assert.Equal(t, otpSecret20, decoded)
+}
+
+func TestOTPURI(t *testing.T) {
+	totp := crypto.NewTOTP(otpSecret20)
+
+	assert.Equal(t,
+		"otpauth://totp/Example%20Co:alice@example.com?algorithm=SHA1&digits=6&issuer=Example%20Co&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		totp.URI("Example Co", "alice@example.com"))
+
+	hotp := &crypto.HOTP{Secret: otpSecret20, Digits: 8, Algorithm: crypto.OTPSHA256}
+
+	assert.Equal(t,
+		"otpauth://hotp/alice?algorithm=SHA256&counter=5&digits=8&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		hotp.URI("", "alice", 5))
+}
@@ -0,0 +1,140 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+const (
+	// passphraseVersion of encrypted format:
+	// version (1 byte) | kdf length (1 byte) | kdf id$params | salt length (1 byte) | salt | nonce | encrypted
+	passphraseVersion byte = 1
+	passphraseKeyLen       = 32
+	passphraseSaltLen      = 16
+)
+
+// ErrInvalidPassphraseHeader error
+var ErrInvalidPassphraseHeader = errors.New("invalid passphrase encrypted header")
+
+// Passphrase crypter derives AES-256 key from Secret passphrase and encrypts payload using AES GCM
+// Salt and KDF params are stored in encrypted header, so only Secret is needed for decryption
+type Passphrase struct {
+	Secret string
+	// KDF used for encryption, Argon2 with default params if not set
+	KDF KDF
+}
+
+// Encrypt payload with key derived from Secret
+func (passphrase *Passphrase) Encrypt(payload []byte) (string, string, string, error) {
+	if strings.TrimSpace(passphrase.Secret) == "" {
+		return "", "", "", ErrMissingSecret
+	}
+
+	kdf := passphrase.KDF
+	if kdf == nil {
+		kdf = NewArgon2()
+	}
+
+	kdfHeader := kdf.ID() + "$" + kdf.Params()
+	if len(kdfHeader) > 0xff {
+		return "", "", "", errors.New("kdf params too long")
+	}
+
+	// Decrypt rejects params exceeding limits, so such input would never be decrypted
+	if _, err := kdfFromParams(kdf.ID(), kdf.Params()); err != nil {
+		return "", "", "", err
+	}
+
+	salt, err := GenerateSalt(passphraseSaltLen)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	key, err := DeriveKey(kdf, passphrase.Secret, salt, passphraseKeyLen)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	header := make([]byte, 0, 3+len(kdfHeader)+len(salt))
+	header = append(header, passphraseVersion, byte(len(kdfHeader)))
+	header = append(header, kdfHeader...)
+	header = append(header, byte(len(salt)))
+	header = append(header, salt...)
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	sealed, err := sealAEAD(gcm, payload, header)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	encrypted := append(header, sealed...)
+
+	return string(encrypted), hex.EncodeToString(encrypted), str.Base64URLEncode(string(encrypted)), nil
+}
+
+// Decrypt input encrypted with key derived from Secret
+func (passphrase *Passphrase) Decrypt(encrypted string) (string, error) {
+	if strings.TrimSpace(passphrase.Secret) == "" {
+		return "", ErrMissingSecret
+	}
+
+	byteIn := []byte(encrypted)
+
+	kdf, salt, header, err := parsePassphraseHeader(byteIn)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := DeriveKey(kdf, passphrase.Secret, salt, passphraseKeyLen)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	decrypted, err := openAEAD(gcm, byteIn[len(header):], header)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decrypted), nil
+}
+
+// parsePassphraseHeader is helper function to get KDF, salt and whole header of encrypted input
+func parsePassphraseHeader(byteIn []byte) (KDF, []byte, []byte, error) {
+	if len(byteIn) < 2 || byteIn[0] != passphraseVersion {
+		return nil, nil, nil, ErrInvalidPassphraseHeader
+	}
+
+	kdfEnd := 2 + int(byteIn[1])
+	if len(byteIn) < kdfEnd+1 {
+		return nil, nil, nil, ErrInvalidPassphraseHeader
+	}
+
+	saltEnd := kdfEnd + 1 + int(byteIn[kdfEnd])
+	if len(byteIn) < saltEnd {
+		return nil, nil, nil, ErrInvalidPassphraseHeader
+	}
+
+	kdfHeader := strings.SplitN(string(byteIn[2:kdfEnd]), "$", 2)
+	if len(kdfHeader) != 2 {
+		return nil, nil, nil, ErrInvalidPassphraseHeader
+	}
+
+	kdf, err := kdfFromParams(kdfHeader[0], kdfHeader[1])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return kdf, byteIn[kdfEnd+1 : saltEnd], byteIn[:saltEnd], nil
+}
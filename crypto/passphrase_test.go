@@ -0,0 +1,112 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestPassphraseEncryptDecrypt(t *testing.T) {
+	kdfs := []crypto.KDF{
+		nil,
+		&crypto.Argon2{Memory: 8 * 1024, Time: 1, Threads: 1},
+		crypto.NewSCrypt(),
+		crypto.HKDF{},
+	}
+
+	for _, kdf := range kdfs {
+		cipher := &crypto.Cipher{
+			Crypter: &crypto.Passphrase{
+				Secret: "correct horse battery staple",
+				KDF:    kdf,
+			},
+		}
+
+		err := cipher.Encrypt([]byte(message))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, cipher.Hex)
+		assert.NotEmpty(t, cipher.Base64)
+
+		// params are read from header, KDF is not needed for decryption
+		decrypter := &crypto.Passphrase{
+			Secret: "correct horse battery staple",
+		}
+
+		decrypted, err := decrypter.Decrypt(cipher.Encrypted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, message, decrypted)
+	}
+}
+
+func TestPassphraseWrongSecret(t *testing.T) {
+	encrypted, _, _, err := (&crypto.Passphrase{Secret: "passphrase", KDF: crypto.HKDF{}}).Encrypt([]byte(message))
+	assert.NoError(t, err)
+
+	_, err = (&crypto.Passphrase{Secret: "other"}).Decrypt(encrypted)
+	assert.Error(t, err)
+
+	tampered := []byte(encrypted)
+	tampered[3] ^= 1
+
+	_, err = (&crypto.Passphrase{Secret: "passphrase"}).Decrypt(string(tampered))
+	assert.Error(t, err)
+}
+
+func TestPassphraseInvalidHeader(t *testing.T) {
+	passphrase := &crypto.Passphrase{
+		Secret: "passphrase",
+	}
+
+	inputs := []string{
+		"",
+		"\x02",
+		"\x01\x20argon2id",
+		"\x01\x0bunknown$a=1\x00",
+	}
+
+	for _, input := range inputs {
+		_, err := passphrase.Decrypt(input)
+
+		assert.Error(t, err)
+	}
+
+	_, _, _, err := (&crypto.Passphrase{}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+}
+
+func TestPassphraseKDFParamsLimit(t *testing.T) {
+	passphrase := &crypto.Passphrase{
+		Secret: "passphrase",
+	}
+
+	kdfHeaders := []string{
+		"argon2id$m=2097152,t=3,p=2",
+		"argon2id$m=65536,t=1000000,p=2",
+		"argon2id$m=65536,t=3,p=255",
+		"scrypt$ln=40,r=8,p=1",
+		"scrypt$ln=20,r=1024,p=1",
+		"scrypt$ln=15,r=8,p=1000000",
+	}
+
+	for _, kdfHeader := range kdfHeaders {
+		input := []byte{1, byte(len(kdfHeader))}
+		input = append(input, kdfHeader...)
+		input = append(input, 16)
+		input = append(input, make([]byte, 16+64)...)
+
+		// rejected before key derivation, so it returns immediately
+		_, err := passphrase.Decrypt(string(input))
+
+		assert.Equal(t, crypto.ErrKDFParamsLimit, err, kdfHeader)
+	}
+
+	argon := crypto.NewArgon2()
+	argon.Time = 100
+
+	_, _, _, err := (&crypto.Passphrase{Secret: "passphrase", KDF: argon}).Encrypt([]byte(message))
+	assert.Equal(t, crypto.ErrKDFParamsLimit, err)
+}
@@ -0,0 +1,162 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcryptMaxLength is max password length in bytes bcrypt uses, the rest is ignored
+const bcryptMaxLength = 72
+
+var (
+	// ErrPasswordTooShort error
+	ErrPasswordTooShort = errors.New("password too short")
+	// ErrPasswordTooLong error
+	ErrPasswordTooLong = errors.New("password too long")
+	// ErrPasswordCharacterClass error
+	ErrPasswordCharacterClass = errors.New("password missing required character class")
+	// ErrPasswordTooWeak error
+	ErrPasswordTooWeak = errors.New("password too weak")
+	// ErrPasswordForbiddenWord error
+	ErrPasswordForbiddenWord = errors.New("password contains forbidden word")
+)
+
+// PasswordPolicy validates passwords before hashing
+// Returned errors wrap one of ErrPassword* errors with description, use errors.Is to check them
+type PasswordPolicy struct {
+	// MinLength in characters
+	MinLength int
+	// MaxLength in bytes, 72 for bcrypt, 0 for no limit
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MinEntropy in bits, see PasswordEntropy
+	MinEntropy float64
+	// ForbiddenWords password must not contain, case insensitive, e.g. app name, username
+	ForbiddenWords []string
+}
+
+// NewPasswordPolicy will initialize default policy: at least 8 characters, at most 72 bytes, 40 bits of entropy
+func NewPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:  8,
+		MaxLength:  bcryptMaxLength,
+		MinEntropy: 40,
+	}
+}
+
+// Validate will return first policy violation of password, nil if password is valid
+func (policy *PasswordPolicy) Validate(password string) error {
+	if length := utf8.RuneCountInString(password); length < policy.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters long", ErrPasswordTooShort, policy.MinLength)
+	}
+
+	if policy.MaxLength > 0 && len(password) > policy.MaxLength {
+		return fmt.Errorf("%w: must be at most %d bytes long", ErrPasswordTooLong, policy.MaxLength)
+	}
+
+	classes := passwordClasses(password)
+
+	required := []struct {
+		required bool
+		present  bool
+		name     string
+	}{
+		{policy.RequireUpper, classes.upper, "uppercase letter"},
+		{policy.RequireLower, classes.lower, "lowercase letter"},
+		{policy.RequireDigit, classes.digit, "digit"},
+		{policy.RequireSymbol, classes.symbol, "symbol"},
+	}
+
+	for _, class := range required {
+		if class.required && !class.present {
+			return fmt.Errorf("%w: must contain at least one %s", ErrPasswordCharacterClass, class.name)
+		}
+	}
+
+	lower := strings.ToLower(password)
+
+	for _, word := range policy.ForbiddenWords {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return fmt.Errorf("%w: must not contain %q", ErrPasswordForbiddenWord, word)
+		}
+	}
+
+	if entropy := PasswordEntropy(password); entropy < policy.MinEntropy {
+		return fmt.Errorf("%w: estimated entropy %.1f bits, at least %.1f required", ErrPasswordTooWeak, entropy, policy.MinEntropy)
+	}
+
+	return nil
+}
+
+// PasswordEntropy will estimate password entropy in bits as length * log2(character pool size)
+// Pool size is sum of character classes used: lowercase (26), uppercase (26), digits (10), symbols (33), other (100)
+// Repeated characters are counted once, it is only an estimate and overrates dictionary words
+func PasswordEntropy(password string) float64 {
+	classes := passwordClasses(password)
+
+	pool := 0
+	if classes.lower {
+		pool += 26
+	}
+	if classes.upper {
+		pool += 26
+	}
+	if classes.digit {
+		pool += 10
+	}
+	if classes.symbol {
+		pool += 33
+	}
+	if classes.other {
+		pool += 100
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	unique := make(map[rune]struct{})
+	for _, r := range password {
+		unique[r] = struct{}{}
+	}
+
+	return float64(len(unique)) * math.Log2(float64(pool))
+}
+
+// characterClasses present in password
+type characterClasses struct {
+	lower  bool
+	upper  bool
+	digit  bool
+	symbol bool
+	other  bool
+}
+
+// passwordClasses is helper function to get character classes present in password
+func passwordClasses(password string) characterClasses {
+	var classes characterClasses
+
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			classes.lower = true
+		case r >= 'A' && r <= 'Z':
+			classes.upper = true
+		case r >= '0' && r <= '9':
+			classes.digit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			classes.symbol = true
+		default:
+			classes.other = true
+		}
+	}
+
+	return classes
+}
@@ -0,0 +1,70 @@
+package crypto_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := crypto.NewPasswordPolicy()
+	policy.RequireUpper = true
+	policy.RequireLower = true
+	policy.RequireDigit = true
+	policy.RequireSymbol = true
+	policy.ForbiddenWords = []string{"godev", "semir"}
+
+	cases := map[string]error{
+		"Tr0ub4dor&3x":             nil,
+		"čćžšđ-Tr0ub4dor&3x":       nil,
+		"Ab1!":                     crypto.ErrPasswordTooShort,
+		strings.Repeat("Ab1!", 19): crypto.ErrPasswordTooLong,
+		"tr0ub4dor&3x":             crypto.ErrPasswordCharacterClass,
+		"TR0UB4DOR&3X":             crypto.ErrPasswordCharacterClass,
+		"Troubador&xx":             crypto.ErrPasswordCharacterClass,
+		"Tr0ub4dor33x":             crypto.ErrPasswordCharacterClass,
+		"My-GoDev-pass1":           crypto.ErrPasswordForbiddenWord,
+		"Aa1!Aa1!Aa1!":             crypto.ErrPasswordTooWeak,
+	}
+
+	for password, expected := range cases {
+		err := policy.Validate(password)
+
+		if expected == nil {
+			assert.NoError(t, err, password)
+			continue
+		}
+
+		assert.True(t, errors.Is(err, expected), "%s: %v", password, err)
+	}
+}
+
+func TestPasswordPolicyDescriptiveError(t *testing.T) {
+	err := crypto.NewPasswordPolicy().Validate("short")
+
+	assert.EqualError(t, err, "password too short: must be at least 8 characters long")
+}
+
+func TestPasswordPolicyMaxLength(t *testing.T) {
+	policy := crypto.NewPasswordPolicy()
+	policy.MinEntropy = 0
+
+	// 72 bytes but only 36 characters
+	assert.NoError(t, policy.Validate(strings.Repeat("čx", 24)))
+	assert.True(t, errors.Is(policy.Validate(strings.Repeat("čx", 25)), crypto.ErrPasswordTooLong))
+
+	policy.MaxLength = 0
+	assert.NoError(t, policy.Validate(strings.Repeat("čx", 100)))
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	assert.Equal(t, 0.0, crypto.PasswordEntropy(""))
+	assert.InDelta(t, 4*3.3219, crypto.PasswordEntropy("1234"), 0.001)
+	assert.InDelta(t, 3*4.7004, crypto.PasswordEntropy("aaabbbccc"), 0.001)
+	assert.InDelta(t, 4*6.5699, crypto.PasswordEntropy("aB3!"), 0.001)
+	assert.True(t, crypto.PasswordEntropy("correct horse battery staple") > 60)
+}
@@ -0,0 +1,148 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pepperID is PHC identifier of peppered hashes
+const pepperID = "pepper"
+
+// ErrNotPeppered error
+var ErrNotPeppered = errors.New("hash is not peppered")
+
+// Pepper password hasher, plain is HMAC-SHA256 of password with server-side secret (pepper) before hashing
+// Pepper is integration point for peppering: use it (e.g. wrapping Registry) instead of Argon2, SCrypt or BCrypt directly,
+// their Hash/Validate do not know about pepper
+// Pepper version is encoded in stored hash, so pepper can be rotated while older hashes are still verified:
+// $pepper$v=1$argon2id$v=19$m=65536,t=3,p=2$salt$hash
+type Pepper struct {
+	// Hasher hashing peppered plain, e.g. Argon2 or Registry
+	Hasher PasswordHasher
+	// Version of pepper used for new hashes
+	Version int
+	// Peppers by version
+	Peppers map[int][]byte
+	// AllowUnpeppered enables verification of hashes made without pepper (e.g. during migration), they need rehash
+	// It is off by default, since anyone able to write plain hash to storage would bypass pepper
+	AllowUnpeppered bool
+}
+
+// NewPepper will initialize Pepper with single pepper version
+func NewPepper(hasher PasswordHasher, version int, pepper []byte) *Pepper {
+	return &Pepper{
+		Hasher:  hasher,
+		Version: version,
+		Peppers: map[int][]byte{version: pepper},
+	}
+}
+
+// ID implements PasswordHasher.ID
+func (pepper *Pepper) ID() string {
+	return pepperID
+}
+
+// Encode implements PasswordHasher.Encode using current pepper Version
+func (pepper *Pepper) Encode(plain string) (string, error) {
+	if plain == "" {
+		return "", ErrMissingPlain
+	}
+
+	peppered, err := pepper.pepper(pepper.Version, plain)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := pepper.Hasher.Encode(peppered)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$%s$v=%d%s", pepperID, pepper.Version, encoded), nil
+}
+
+// Verify implements PasswordHasher.Verify using pepper version encoded hash was made with
+// ErrNotPeppered is returned for hashes made without pepper, unless AllowUnpeppered is set
+func (pepper *Pepper) Verify(plain, encoded string) (bool, error) {
+	version, inner, ok, err := splitPeppered(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		if !pepper.AllowUnpeppered {
+			return false, ErrNotPeppered
+		}
+
+		return pepper.Hasher.Verify(plain, encoded)
+	}
+
+	peppered, err := pepper.pepper(version, plain)
+	if err != nil {
+		return false, err
+	}
+
+	return pepper.Hasher.Verify(peppered, inner)
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+// Hashes made without pepper, with older pepper version or outdated Hasher params need rehash
+func (pepper *Pepper) NeedsRehash(encoded string) bool {
+	version, inner, ok, err := splitPeppered(encoded)
+	if err != nil || !ok || version != pepper.Version {
+		return true
+	}
+
+	return pepper.Hasher.NeedsRehash(inner)
+}
+
+// VerifyAndUpgrade will verify plain against encoded hash and, when valid and outdated,
+// return newEncoded hash made with current pepper, newEncoded is empty if no upgrade is needed
+func (pepper *Pepper) VerifyAndUpgrade(plain, encoded string) (bool, string, error) {
+	return verifyAndUpgrade(pepper, plain, encoded)
+}
+
+// pepper is helper function to calculate base64 encoded HMAC-SHA256 of plain using pepper version
+func (pepper *Pepper) pepper(version int, plain string) (string, error) {
+	key, ok := pepper.Peppers[version]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	if len(key) == 0 {
+		return "", ErrMissingSecret
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(plain))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// splitPeppered is helper function to split peppered hash to pepper version and inner hash
+// ok is false if encoded is not peppered
+func splitPeppered(encoded string) (int, string, bool, error) {
+	prefix := "$" + pepperID + "$v="
+	if !strings.HasPrefix(encoded, prefix) {
+		return 0, "", false, nil
+	}
+
+	rest := encoded[len(prefix):]
+
+	i := strings.Index(rest, "$")
+	if i <= 0 {
+		return 0, "", false, ErrInvalidHash
+	}
+
+	version, err := strconv.Atoi(rest[:i])
+	if err != nil {
+		return 0, "", false, ErrInvalidHash
+	}
+
+	return version, rest[i:], true, nil
+}
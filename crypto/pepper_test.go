@@ -0,0 +1,115 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestPepperEncodeVerify(t *testing.T) {
+	pepper := crypto.NewPepper(crypto.NewRegistry(testBCrypt()), 1, []byte("pepper-v1"))
+
+	encoded, err := pepper.Encode(message)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$pepper$v=1$2a$04$"))
+
+	valid, err := pepper.Verify(message, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.False(t, pepper.NeedsRehash(encoded))
+
+	valid, err = pepper.Verify("other", encoded)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// hash is useless without pepper
+	other := crypto.NewPepper(testBCrypt(), 1, []byte("other-pepper"))
+
+	valid, err = other.Verify(message, encoded)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestPepperRotation(t *testing.T) {
+	hasher := crypto.NewRegistry(testBCrypt())
+
+	old := crypto.NewPepper(hasher, 1, []byte("pepper-v1"))
+
+	oldEncoded, err := old.Encode(message)
+	assert.NoError(t, err)
+
+	pepper := crypto.NewPepper(hasher, 2, []byte("pepper-v2"))
+	pepper.Peppers[1] = []byte("pepper-v1")
+
+	assert.True(t, pepper.NeedsRehash(oldEncoded))
+
+	valid, upgraded, err := pepper.VerifyAndUpgrade(message, oldEncoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.True(t, strings.HasPrefix(upgraded, "$pepper$v=2$"))
+
+	valid, upgraded, err = pepper.VerifyAndUpgrade(message, upgraded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Empty(t, upgraded)
+
+	// removed pepper version
+	delete(pepper.Peppers, 1)
+
+	_, err = pepper.Verify(message, oldEncoded)
+	assert.Equal(t, crypto.ErrUnknownKey, err)
+}
+
+func TestPepperUnpeppered(t *testing.T) {
+	bCrypt := testBCrypt()
+
+	unpeppered, err := bCrypt.Encode(message)
+	assert.NoError(t, err)
+
+	pepper := crypto.NewPepper(bCrypt, 1, []byte("pepper-v1"))
+
+	assert.True(t, pepper.NeedsRehash(unpeppered))
+
+	// unpeppered hashes are rejected by default
+	valid, err := pepper.Verify(message, unpeppered)
+
+	assert.Equal(t, crypto.ErrNotPeppered, err)
+	assert.False(t, valid)
+
+	pepper.AllowUnpeppered = true
+
+	valid, upgraded, err := pepper.VerifyAndUpgrade(message, unpeppered)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.True(t, strings.HasPrefix(upgraded, "$pepper$v=1$"))
+}
+
+func TestPepperInvalidHash(t *testing.T) {
+	pepper := crypto.NewPepper(testBCrypt(), 1, []byte("pepper-v1"))
+
+	_, err := pepper.Verify(message, "$pepper$v=x$2a$04$invalid")
+	assert.Equal(t, crypto.ErrInvalidHash, err)
+
+	_, err = pepper.Verify(message, "$pepper$v=1")
+	assert.Equal(t, crypto.ErrInvalidHash, err)
+
+	_, err = pepper.Encode("")
+	assert.Equal(t, crypto.ErrMissingPlain, err)
+}
+
+func testBCrypt() *crypto.BCrypt {
+	bCrypt := crypto.NewBCrypt()
+	bCrypt.Cost = 4
+
+	return bCrypt
+}
@@ -0,0 +1,109 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// phcEncoding is used for salt and hash in PHC string format (standard base64 without padding)
+var phcEncoding = base64.RawStdEncoding
+
+// phcHash represents parsed PHC string: $id[$v=version][$param=value(,param=value)*][$salt[$hash]]
+type phcHash struct {
+	ID      string
+	Version int
+	Params  map[string]int
+	Salt    []byte
+	Hash    []byte
+}
+
+// parsePHC will parse PHC string with numeric params
+func parsePHC(encoded string) (*phcHash, error) {
+	if !strings.HasPrefix(encoded, "$") {
+		return nil, ErrInvalidHash
+	}
+
+	vals := strings.Split(encoded[1:], "$")
+	if vals[0] == "" {
+		return nil, ErrInvalidHash
+	}
+
+	phc := &phcHash{
+		ID:     vals[0],
+		Params: make(map[string]int),
+	}
+	vals = vals[1:]
+
+	if len(vals) > 0 && strings.HasPrefix(vals[0], "v=") {
+		version, err := strconv.Atoi(strings.TrimPrefix(vals[0], "v="))
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		phc.Version = version
+		vals = vals[1:]
+	}
+
+	if len(vals) > 0 && strings.Contains(vals[0], "=") {
+		params, err := parsePHCParams(vals[0])
+		if err != nil {
+			return nil, err
+		}
+		phc.Params = params
+		vals = vals[1:]
+	}
+
+	if len(vals) > 2 {
+		return nil, ErrInvalidHash
+	}
+
+	var err error
+
+	if len(vals) > 0 {
+		if phc.Salt, err = phcEncoding.DecodeString(vals[0]); err != nil {
+			return nil, ErrInvalidHash
+		}
+	}
+
+	if len(vals) > 1 {
+		if phc.Hash, err = phcEncoding.DecodeString(vals[1]); err != nil {
+			return nil, ErrInvalidHash
+		}
+	}
+
+	return phc, nil
+}
+
+// parsePHCParams will parse numeric PHC params: param=value(,param=value)*
+func parsePHCParams(encoded string) (map[string]int, error) {
+	params := make(map[string]int)
+
+	if encoded == "" {
+		return params, nil
+	}
+
+	for _, param := range strings.Split(encoded, ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrInvalidHash
+		}
+
+		value, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		params[kv[0]] = value
+	}
+
+	return params, nil
+}
+
+// param will return required PHC param or ErrInvalidHash if it is missing
+func (phc *phcHash) param(name string) (int, error) {
+	value, ok := phc.Params[name]
+	if !ok {
+		return 0, ErrInvalidHash
+	}
+
+	return value, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/bits"
 	"strconv"
 	"strings"
 
@@ -13,13 +14,16 @@ import (
 
 // Credits to: https://github.com/elithrar/simple-scrypt/blob/master/scrypt.go
 
+// scryptID is PHC identifier for SCrypt
+const scryptID = "scrypt"
+
 // SCrypt hashing algorithm
 type SCrypt struct {
 	Plain   string
 	Hashed  string
 	DK      []byte
 	Salt    []byte
-	N       int // 32768, should be the highest power of 2 derived within 100 milliseconds
+	N       int // 32768, should be the highest power of 2 derived within 100 milliseconds, see CalibrateSCrypt
 	R       int // 8
 	P       int // 1
 	KeyLen  int // 32
@@ -63,30 +67,108 @@ func (sCrypt *SCrypt) Hash() error {
 
 // Validate sCrypt.Plain against sCrypt.Hashed
 func (sCrypt *SCrypt) Validate() bool {
-	existing, err := decodeSCryptHash(sCrypt.Hashed)
+	valid, err := sCrypt.Verify(sCrypt.Plain, sCrypt.Hashed)
 	if err != nil {
 		return false
 	}
 
-	dk, err := scrypt.Key([]byte(sCrypt.Plain), existing.Salt, existing.N, existing.R, existing.P, existing.KeyLen)
+	return valid
+}
+
+// ID implements PasswordHasher.ID
+func (sCrypt *SCrypt) ID() string {
+	return scryptID
+}
+
+// Encode implements PasswordHasher.Encode, sCrypt itself is not modified
+func (sCrypt *SCrypt) Encode(plain string) (string, error) {
+	if plain == "" {
+		return "", ErrMissingPlain
+	}
+
+	if sCrypt.N <= 1 || sCrypt.N&(sCrypt.N-1) != 0 {
+		return "", errors.New("scrypt N must be power of 2")
+	}
+
+	salt, err := GenerateSalt(sCrypt.SaltLen)
 	if err != nil {
-		return false
+		return "", err
+	}
+
+	dk, err := scrypt.Key([]byte(plain), salt, sCrypt.N, sCrypt.R, sCrypt.P, sCrypt.KeyLen)
+	if err != nil {
+		return "", err
 	}
 
-	if subtle.ConstantTimeCompare(existing.DK, dk) == 1 {
+	return fmt.Sprintf("$%s$%s$%s$%s",
+		scryptID, sCrypt.Params(),
+		phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(dk)), nil
+}
+
+// Verify implements PasswordHasher.Verify, both PHC and legacy encoded hashes are accepted
+func (sCrypt *SCrypt) Verify(plain, encoded string) (bool, error) {
+	existing, err := decodeSCryptHash(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	dk, err := scrypt.Key([]byte(plain), existing.Salt, existing.N, existing.R, existing.P, existing.KeyLen)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(existing.DK, dk) == 1, nil
+}
+
+// Params implements KDF.Params, N is stored as its base 2 logarithm (ln)
+func (sCrypt *SCrypt) Params() string {
+	return fmt.Sprintf("ln=%d,r=%d,p=%d", bits.TrailingZeros(uint(sCrypt.N)), sCrypt.R, sCrypt.P)
+}
+
+// Key implements KDF.Key
+func (sCrypt *SCrypt) Key(secret, salt []byte, keyLen int) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	if keyLen <= 0 {
+		return nil, ErrInvalidKeyLen
+	}
+
+	return scrypt.Key(secret, salt, sCrypt.N, sCrypt.R, sCrypt.P, keyLen)
+}
+
+// NeedsRehash implements PasswordHasher.NeedsRehash
+// Legacy encoded hashes and hashes with params different from sCrypt need rehash
+func (sCrypt *SCrypt) NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, "$") {
 		return true
 	}
 
-	return false
+	existing, err := decodeSCryptHash(encoded)
+	if err != nil {
+		return true
+	}
+
+	return existing.N != sCrypt.N ||
+		existing.R != sCrypt.R ||
+		existing.P != sCrypt.P ||
+		existing.SaltLen != sCrypt.SaltLen ||
+		existing.KeyLen != sCrypt.KeyLen
 }
 
-// decodeSCryptHash
+// decodeSCryptHash will decode PHC ($scrypt$ln=15,r=8,p=1$salt$dk)
+// or legacy (32768$8$1$salt$dk) encoded hash
 func decodeSCryptHash(hash string) (*SCrypt, error) {
+	if strings.HasPrefix(hash, "$") {
+		return decodeSCryptPHC(hash)
+	}
+
 	vals := strings.Split(hash, "$")
 
 	// P, N, R, salt, scrypt derived key
 	if len(vals) != 5 {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 
 	sCrypt := &SCrypt{}
@@ -94,30 +176,86 @@ func decodeSCryptHash(hash string) (*SCrypt, error) {
 
 	sCrypt.N, err = strconv.Atoi(vals[0])
 	if err != nil {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 
 	sCrypt.R, err = strconv.Atoi(vals[1])
 	if err != nil {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 
 	sCrypt.P, err = strconv.Atoi(vals[2])
 	if err != nil {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 
 	sCrypt.Salt, err = hex.DecodeString(vals[3])
 	if err != nil {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 	sCrypt.SaltLen = len(sCrypt.Salt)
 
 	sCrypt.DK, err = hex.DecodeString(vals[4])
 	if err != nil {
-		return nil, errors.New("invalid hash")
+		return nil, ErrInvalidHash
 	}
 	sCrypt.KeyLen = len(sCrypt.DK)
 
+	// empty DK would match any password
+	if len(sCrypt.Salt) == 0 || len(sCrypt.DK) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	return sCrypt, nil
+}
+
+// decodeSCryptPHC is helper function to decode scrypt PHC string
+func decodeSCryptPHC(hash string) (*SCrypt, error) {
+	phc, err := parsePHC(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if phc.ID != scryptID || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, ErrInvalidHash
+	}
+
+	sCrypt, err := sCryptFromParams(phc)
+	if err != nil {
+		return nil, err
+	}
+
+	sCrypt.Salt = phc.Salt
+	sCrypt.SaltLen = len(phc.Salt)
+	sCrypt.DK = phc.Hash
+	sCrypt.KeyLen = len(phc.Hash)
+
 	return sCrypt, nil
 }
+
+// sCryptFromParams is helper function to create SCrypt from ln, r and p PHC params
+func sCryptFromParams(phc *phcHash) (*SCrypt, error) {
+	ln, err := phc.param("ln")
+	if err != nil {
+		return nil, err
+	}
+	if ln <= 0 || ln >= 63 {
+		return nil, ErrInvalidHash
+	}
+
+	r, err := phc.param("r")
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := phc.param("p")
+	if err != nil {
+		return nil, err
+	}
+
+	return &SCrypt{
+		N: 1 << uint(ln),
+		R: r,
+		P: p,
+	}, nil
+}
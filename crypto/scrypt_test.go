@@ -1,6 +1,7 @@
 package crypto_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/semirm-dev/godev/crypto"
@@ -58,3 +59,66 @@ func TestSCryptHashPlainMissing(t *testing.T) {
 
 	assert.Equal(t, crypto.ErrMissingPlain, err)
 }
+
+func TestSCryptEncode(t *testing.T) {
+	sCrypt := crypto.NewSCrypt()
+
+	encoded, err := sCrypt.Encode(plainSCrypt)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$scrypt$ln=15,r=8,p=1$"))
+	assert.Empty(t, sCrypt.Hashed)
+
+	valid, err := sCrypt.Verify(plainSCrypt, encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSCryptVerifyLegacy(t *testing.T) {
+	sCrypt := crypto.NewSCrypt()
+
+	valid, err := sCrypt.Verify(plainSCrypt, hashedSCrypt)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = sCrypt.Verify("invalid", hashedSCrypt)
+
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestSCryptEncodeInvalidN(t *testing.T) {
+	sCrypt := crypto.NewSCrypt()
+	sCrypt.N = 1000
+
+	_, err := sCrypt.Encode(plainSCrypt)
+
+	assert.Error(t, err)
+}
+
+func TestSCryptNeedsRehash(t *testing.T) {
+	sCrypt := crypto.NewSCrypt()
+
+	encoded, err := sCrypt.Encode(plainSCrypt)
+	assert.NoError(t, err)
+
+	assert.False(t, sCrypt.NeedsRehash(encoded))
+	assert.True(t, sCrypt.NeedsRehash(hashedSCrypt))
+
+	sCrypt.N = 65536
+
+	assert.True(t, sCrypt.NeedsRehash(encoded))
+}
+
+func TestSCryptVerifyEmptyHash(t *testing.T) {
+	sCrypt := crypto.NewSCrypt()
+
+	for _, hashed := range []string{"16$8$1$00$", "16$8$1$$00", "$scrypt$ln=4,r=8,p=1$c2FsdA$"} {
+		valid, err := sCrypt.Verify("any password", hashed)
+
+		assert.Error(t, err, hashed)
+		assert.False(t, valid, hashed)
+	}
+}
@@ -0,0 +1,147 @@
+package crypto_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+const downloadURL = "https://example.com/files/report.pdf?user=1"
+
+func TestSignURL(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+
+	signed, err := signer.SignURL(downloadURL, time.Now().Add(time.Hour), "get", http.MethodHead)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/files/report.pdf", u.Path)
+	assert.Equal(t, "1", u.Query().Get("user"))
+	assert.Equal(t, "GET,HEAD", u.Query().Get("methods"))
+	assert.NotEmpty(t, u.Query().Get("expires"))
+	assert.NotEmpty(t, u.Query().Get("signature"))
+
+	assert.NoError(t, signer.VerifyURL(signed, http.MethodGet))
+	assert.NoError(t, signer.VerifyURL(signed, "head"))
+	assert.Equal(t, crypto.ErrMethodNotAllowed, signer.VerifyURL(signed, http.MethodPost))
+}
+
+func TestSignURLAnyMethod(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+
+	signed, err := signer.SignURL(downloadURL, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.VerifyURL(signed, http.MethodPost))
+}
+
+func TestSignURLFragment(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+
+	signed, err := signer.SignURL(downloadURL+"#page=2", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "page=2", u.Fragment)
+
+	assert.NoError(t, signer.VerifyURL(signed, http.MethodGet))
+
+	// fragment is not sent to server
+	u.Fragment = ""
+	assert.NoError(t, signer.VerifyURL(u.String(), http.MethodGet))
+}
+
+func TestVerifyURLRequestURI(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+
+	signed, err := signer.SignURL(downloadURL, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+
+	// server behind proxy sees only request URI, possibly with different scheme and host
+	assert.NoError(t, signer.VerifyURL(u.RequestURI(), http.MethodGet))
+
+	u.Scheme = "http"
+	u.Host = "internal:8080"
+	assert.NoError(t, signer.VerifyURL(u.String(), http.MethodGet))
+}
+
+func TestSignURLBindHost(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+	signer.BindHost = true
+
+	signed, err := signer.SignURL(downloadURL, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.VerifyURL(signed, http.MethodGet))
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, crypto.ErrInvalidSignature, signer.VerifyURL(u.RequestURI(), http.MethodGet))
+
+	u.Host = "evil.example.com"
+	assert.Equal(t, crypto.ErrInvalidSignature, signer.VerifyURL(u.String(), http.MethodGet))
+}
+
+func TestVerifyURLExpired(t *testing.T) {
+	now := time.Now()
+
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+	signer.Now = func() time.Time {
+		return now
+	}
+
+	signed, err := signer.SignURL(downloadURL, now.Add(time.Minute))
+	assert.NoError(t, err)
+
+	assert.NoError(t, signer.VerifyURL(signed, http.MethodGet))
+
+	now = now.Add(2 * time.Minute)
+
+	assert.Equal(t, crypto.ErrURLExpired, signer.VerifyURL(signed, http.MethodGet))
+}
+
+func TestVerifyURLTampered(t *testing.T) {
+	signer := crypto.NewURLSigner("k1", []byte("url-signing-key"))
+
+	signed, err := signer.SignURL(downloadURL, time.Now().Add(time.Hour), http.MethodGet)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+
+	tampered := []func(u *url.URL){
+		func(u *url.URL) { u.Path = "/files/other.pdf" },
+		func(u *url.URL) { setQuery(u, "user", "2") },
+		func(u *url.URL) { setQuery(u, "methods", "GET,POST") },
+		func(u *url.URL) { setQuery(u, "expires", "9999999999") },
+		func(u *url.URL) { setQuery(u, "signature", "") },
+	}
+
+	for _, tamper := range tampered {
+		copied := *u
+		tamper(&copied)
+
+		assert.Equal(t, crypto.ErrInvalidSignature, signer.VerifyURL(copied.String(), http.MethodGet))
+	}
+
+	other := crypto.NewURLSigner("k1", []byte("other-signing-key"))
+	assert.Equal(t, crypto.ErrInvalidSignature, other.VerifyURL(signed, http.MethodGet))
+}
+
+func setQuery(u *url.URL, key, value string) {
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+}
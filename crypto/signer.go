@@ -0,0 +1,339 @@
+package crypto
+
+import (
+	gocrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+)
+
+const (
+	// AlgorithmEdDSA is Ed25519 signature algorithm
+	AlgorithmEdDSA = "EdDSA"
+	// AlgorithmES256 is ECDSA P-256 with SHA-256 signature algorithm
+	AlgorithmES256 = "ES256"
+	// AlgorithmPS256 is RSA-PSS with SHA-256 signature algorithm
+	AlgorithmPS256 = "PS256"
+)
+
+// es256KeySize is size of r and s in ES256 signature
+const es256KeySize = 32
+
+// ErrInvalidSignature error
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signer for digital signatures
+type Signer interface {
+	// Algorithm returns signature algorithm, EdDSA, ES256, PS256
+	Algorithm() string
+	// Sign returns signature of payload
+	Sign(payload []byte) ([]byte, error)
+}
+
+// Verifier for digital signatures
+type Verifier interface {
+	// Algorithm returns signature algorithm, EdDSA, ES256, PS256
+	Algorithm() string
+	// Verify returns ErrInvalidSignature if signature of payload is not valid
+	Verify(payload, signature []byte) error
+}
+
+// Ed25519Signer signs using Ed25519
+type Ed25519Signer struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+// Ed25519Verifier verifies Ed25519 signatures
+type Ed25519Verifier struct {
+	PublicKey ed25519.PublicKey
+}
+
+// ECDSASigner signs using ECDSA P-256 and SHA-256, signature is r|s (64 bytes)
+type ECDSASigner struct {
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// ECDSAVerifier verifies ECDSA P-256 and SHA-256 signatures
+type ECDSAVerifier struct {
+	PublicKey *ecdsa.PublicKey
+}
+
+// RSAPSSSigner signs using RSA-PSS and SHA-256
+type RSAPSSSigner struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+// RSAPSSVerifier verifies RSA-PSS and SHA-256 signatures
+type RSAPSSVerifier struct {
+	PublicKey *rsa.PublicKey
+}
+
+// pssOptions, salt length equals hash length as in PS256
+var pssOptions = &rsa.PSSOptions{
+	SaltLength: rsa.PSSSaltLengthEqualsHash,
+	Hash:       gocrypto.SHA256,
+}
+
+// NewSigner will return Signer for given private key
+// Supported keys: ed25519.PrivateKey, *ecdsa.PrivateKey (P-256), *rsa.PrivateKey
+func NewSigner(key gocrypto.PrivateKey) (Signer, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return &Ed25519Signer{PrivateKey: k}, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrInvalidKey
+		}
+		return &ECDSASigner{PrivateKey: k}, nil
+	case *rsa.PrivateKey:
+		return &RSAPSSSigner{PrivateKey: k}, nil
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+// NewVerifier will return Verifier for given public key
+// Supported keys: ed25519.PublicKey, *ecdsa.PublicKey (P-256), *rsa.PublicKey
+func NewVerifier(key gocrypto.PublicKey) (Verifier, error) {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return &Ed25519Verifier{PublicKey: k}, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrInvalidKey
+		}
+		return &ECDSAVerifier{PublicKey: k}, nil
+	case *rsa.PublicKey:
+		return &RSAPSSVerifier{PublicKey: k}, nil
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+// GenerateEd25519Key will generate Ed25519 private key
+func GenerateEd25519Key() (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+
+	return priv, err
+}
+
+// GenerateECDSAKey will generate ECDSA P-256 private key
+func GenerateECDSAKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// GenerateRSAKey will generate RSA private key, bits should be at least 2048
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// MarshalPrivateKeyPEM will encode private key as PKCS #8 PEM block
+func MarshalPrivateKeyPEM(key gocrypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM will encode public key as PKIX PEM block
+func MarshalPublicKeyPEM(key gocrypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM will decode PKCS #8, PKCS #1 (RSA) or SEC 1 (EC) PEM encoded private key
+func ParsePrivateKeyPEM(data []byte) (gocrypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+// ParsePublicKeyPEM will decode PKIX PEM encoded public key
+func ParsePublicKeyPEM(data []byte) (gocrypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidKey
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// SignReader will return detached signature of SHA-256 digest of r
+// It must be verified with VerifyReader, signature differs from the one returned by Signer.Sign for the same content
+func SignReader(signer Signer, r io.Reader) ([]byte, error) {
+	digest, err := readerDigest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Sign(digest)
+}
+
+// VerifyReader will verify detached signature produced by SignReader
+func VerifyReader(verifier Verifier, r io.Reader, signature []byte) error {
+	digest, err := readerDigest(r)
+	if err != nil {
+		return err
+	}
+
+	return verifier.Verify(digest, signature)
+}
+
+// Algorithm implements Signer.Algorithm
+func (signer *Ed25519Signer) Algorithm() string {
+	return AlgorithmEdDSA
+}
+
+// Sign implements Signer.Sign
+func (signer *Ed25519Signer) Sign(payload []byte) ([]byte, error) {
+	if len(signer.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519.Sign(signer.PrivateKey, payload), nil
+}
+
+// Algorithm implements Verifier.Algorithm
+func (verifier *Ed25519Verifier) Algorithm() string {
+	return AlgorithmEdDSA
+}
+
+// Verify implements Verifier.Verify
+func (verifier *Ed25519Verifier) Verify(payload, signature []byte) error {
+	if len(verifier.PublicKey) != ed25519.PublicKeySize {
+		return ErrInvalidKey
+	}
+
+	if !ed25519.Verify(verifier.PublicKey, payload, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Algorithm implements Signer.Algorithm
+func (signer *ECDSASigner) Algorithm() string {
+	return AlgorithmES256
+}
+
+// Sign implements Signer.Sign
+func (signer *ECDSASigner) Sign(payload []byte) ([]byte, error) {
+	if signer.PrivateKey == nil {
+		return nil, ErrInvalidKey
+	}
+
+	digest := sha256.Sum256(payload)
+
+	r, s, err := ecdsa.Sign(rand.Reader, signer.PrivateKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+
+	signature := make([]byte, 2*es256KeySize)
+	copy(signature[es256KeySize-len(rBytes):es256KeySize], rBytes)
+	copy(signature[2*es256KeySize-len(sBytes):], sBytes)
+
+	return signature, nil
+}
+
+// Algorithm implements Verifier.Algorithm
+func (verifier *ECDSAVerifier) Algorithm() string {
+	return AlgorithmES256
+}
+
+// Verify implements Verifier.Verify
+func (verifier *ECDSAVerifier) Verify(payload, signature []byte) error {
+	if verifier.PublicKey == nil {
+		return ErrInvalidKey
+	}
+
+	if len(signature) != 2*es256KeySize {
+		return ErrInvalidSignature
+	}
+
+	r := new(big.Int).SetBytes(signature[:es256KeySize])
+	s := new(big.Int).SetBytes(signature[es256KeySize:])
+
+	digest := sha256.Sum256(payload)
+
+	if !ecdsa.Verify(verifier.PublicKey, digest[:], r, s) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Algorithm implements Signer.Algorithm
+func (signer *RSAPSSSigner) Algorithm() string {
+	return AlgorithmPS256
+}
+
+// Sign implements Signer.Sign
+func (signer *RSAPSSSigner) Sign(payload []byte) ([]byte, error) {
+	if signer.PrivateKey == nil {
+		return nil, ErrInvalidKey
+	}
+
+	digest := sha256.Sum256(payload)
+
+	return rsa.SignPSS(rand.Reader, signer.PrivateKey, gocrypto.SHA256, digest[:], pssOptions)
+}
+
+// Algorithm implements Verifier.Algorithm
+func (verifier *RSAPSSVerifier) Algorithm() string {
+	return AlgorithmPS256
+}
+
+// Verify implements Verifier.Verify
+func (verifier *RSAPSSVerifier) Verify(payload, signature []byte) error {
+	if verifier.PublicKey == nil {
+		return ErrInvalidKey
+	}
+
+	digest := sha256.Sum256(payload)
+
+	if err := rsa.VerifyPSS(verifier.PublicKey, gocrypto.SHA256, digest[:], signature, pssOptions); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// readerDigest is helper function to calculate SHA-256 digest of r
+func readerDigest(r io.Reader) ([]byte, error) {
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, r); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
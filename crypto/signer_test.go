@@ -0,0 +1,163 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+// Ed25519: RFC 8032, section 7.1, TEST 2
+var (
+	ed25519Seed      = "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb"
+	ed25519Public    = "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"
+	ed25519Message   = "72"
+	ed25519Signature = "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"
+)
+
+// ECDSA P-256 with SHA-256, message "sample": RFC 6979, appendix A.2.5
+var (
+	ecdsaX         = "60fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6"
+	ecdsaY         = "7903fe1008b8bc99a41ae9e95628bc64f2f1b20c2d7e9f5177a3c294d4462299"
+	ecdsaSignature = "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716" +
+		"f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8"
+)
+
+func TestEd25519KnownAnswer(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(decodeHex(t, ed25519Seed))
+
+	assert.Equal(t, decodeHex(t, ed25519Public), []byte(priv.Public().(ed25519.PublicKey)))
+
+	signer, err := crypto.NewSigner(priv)
+	assert.NoError(t, err)
+
+	signature, err := signer.Sign(decodeHex(t, ed25519Message))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ed25519Signature, hex.EncodeToString(signature))
+
+	verifier, err := crypto.NewVerifier(priv.Public())
+	assert.NoError(t, err)
+
+	assert.NoError(t, verifier.Verify(decodeHex(t, ed25519Message), signature))
+	assert.Equal(t, crypto.ErrInvalidSignature, verifier.Verify([]byte("other"), signature))
+}
+
+func TestECDSAKnownAnswer(t *testing.T) {
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(decodeHex(t, ecdsaX)),
+		Y:     new(big.Int).SetBytes(decodeHex(t, ecdsaY)),
+	}
+
+	verifier, err := crypto.NewVerifier(pub)
+	assert.NoError(t, err)
+
+	assert.Equal(t, crypto.AlgorithmES256, verifier.Algorithm())
+	assert.NoError(t, verifier.Verify([]byte("sample"), decodeHex(t, ecdsaSignature)))
+	assert.Equal(t, crypto.ErrInvalidSignature, verifier.Verify([]byte("test"), decodeHex(t, ecdsaSignature)))
+}
+
+func TestSignVerify(t *testing.T) {
+	ed25519Key, err := crypto.GenerateEd25519Key()
+	assert.NoError(t, err)
+
+	ecdsaKey, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	rsaKey, err := crypto.GenerateRSAKey(2048)
+	assert.NoError(t, err)
+
+	cases := map[string]struct {
+		private interface{}
+		public  interface{}
+	}{
+		crypto.AlgorithmEdDSA: {ed25519Key, ed25519Key.Public()},
+		crypto.AlgorithmES256: {ecdsaKey, ecdsaKey.Public()},
+		crypto.AlgorithmPS256: {rsaKey, rsaKey.Public()},
+	}
+
+	for algorithm, c := range cases {
+		signer, err := crypto.NewSigner(c.private)
+		assert.NoError(t, err)
+		assert.Equal(t, algorithm, signer.Algorithm())
+
+		verifier, err := crypto.NewVerifier(c.public)
+		assert.NoError(t, err)
+		assert.Equal(t, algorithm, verifier.Algorithm())
+
+		signature, err := signer.Sign([]byte(message))
+		assert.NoError(t, err)
+
+		assert.NoError(t, verifier.Verify([]byte(message), signature), algorithm)
+		assert.Equal(t, crypto.ErrInvalidSignature, verifier.Verify([]byte("other"), signature), algorithm)
+
+		detached, err := crypto.SignReader(signer, bytes.NewReader([]byte(message)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, crypto.VerifyReader(verifier, bytes.NewReader([]byte(message)), detached), algorithm)
+		assert.Equal(t, crypto.ErrInvalidSignature, crypto.VerifyReader(verifier, bytes.NewReader([]byte("other")), detached), algorithm)
+	}
+}
+
+func TestKeyPEM(t *testing.T) {
+	ed25519Key, err := crypto.GenerateEd25519Key()
+	assert.NoError(t, err)
+
+	ecdsaKey, err := crypto.GenerateECDSAKey()
+	assert.NoError(t, err)
+
+	cases := []struct {
+		private interface{}
+		public  interface{}
+	}{
+		{ed25519Key, ed25519Key.Public()},
+		{ecdsaKey, ecdsaKey.Public()},
+	}
+
+	for _, c := range cases {
+		privPEM, err := crypto.MarshalPrivateKeyPEM(c.private)
+		assert.NoError(t, err)
+
+		parsedPriv, err := crypto.ParsePrivateKeyPEM(privPEM)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.private, parsedPriv)
+
+		pubPEM, err := crypto.MarshalPublicKeyPEM(c.public)
+		assert.NoError(t, err)
+
+		parsedPub, err := crypto.ParsePublicKeyPEM(pubPEM)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.public, parsedPub)
+	}
+
+	_, err = crypto.ParsePrivateKeyPEM([]byte("invalid"))
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+
+	_, err = crypto.ParsePublicKeyPEM([]byte("invalid"))
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+}
+
+func TestNewSignerUnsupportedKey(t *testing.T) {
+	_, err := crypto.NewSigner("key")
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, err = crypto.NewSigner(p384)
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+
+	_, err = crypto.NewVerifier(p384.Public())
+	assert.Equal(t, crypto.ErrInvalidKey, err)
+}
@@ -0,0 +1,264 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Encrypted stream format:
+//
+//	header:  version (1 byte) | segment size (4 bytes) | salt (32 bytes) | nonce prefix (7 bytes)
+//	segment: AES GCM sealed segment of at most segment size plain bytes
+//
+// Segments are sealed with per-stream subkey derived by HKDF-SHA256 from key and random salt (as in STREAM/Tink),
+// so nonces only need to be unique within one stream.
+// Each segment is sealed with nonce: nonce prefix | segment counter (4 bytes) | final segment flag (1 byte)
+// and header as additional data, so reordering, truncating or appending segments fails authentication.
+const (
+	streamVersion       byte = 1
+	streamSegmentSize        = 64 * 1024
+	streamMaxSegment         = 16 * 1024 * 1024
+	streamSaltSize           = 32
+	streamNoncePrefix        = 7
+	streamHeaderSize         = 1 + 4 + streamSaltSize + streamNoncePrefix
+	streamLastSegment   byte = 1
+	streamMiddleSegment byte = 0
+	// streamKeyInfo is HKDF info of stream subkey
+	streamKeyInfo = "godev stream subkey"
+)
+
+// ErrInvalidStream error
+var ErrInvalidStream = errors.New("invalid or truncated encrypted stream")
+
+// ErrStreamClosed error
+var ErrStreamClosed = errors.New("encrypted stream already closed")
+
+// encryptWriter encrypts written data in segments
+type encryptWriter struct {
+	w       io.Writer
+	gcm     cipher.AEAD
+	header  []byte
+	counter uint32
+	buf     []byte
+	sealed  []byte
+	closed  bool
+}
+
+// decryptReader decrypts segments produced by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	gcm     cipher.AEAD
+	header  []byte
+	counter uint32
+	segment []byte
+	plain   []byte
+	done    bool
+}
+
+// NewEncryptWriter will return writer which encrypts data written to it with AES GCM and writes it to w
+// Close must be called to write final segment, it does not close w
+func NewEncryptWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	header := make([]byte, streamHeaderSize)
+	header[0] = streamVersion
+	binary.BigEndian.PutUint32(header[1:5], streamSegmentSize)
+
+	random, err := GenerateSalt(streamSaltSize + streamNoncePrefix)
+	if err != nil {
+		return nil, err
+	}
+	copy(header[5:], random)
+
+	gcm, err := newStreamGCM(key, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		gcm:    gcm,
+		header: header,
+		buf:    make([]byte, 0, streamSegmentSize),
+		sealed: make([]byte, 0, streamSegmentSize+gcm.Overhead()),
+	}, nil
+}
+
+// Write implements io.Writer
+func (writer *encryptWriter) Write(p []byte) (int, error) {
+	if writer.closed {
+		return 0, ErrStreamClosed
+	}
+
+	written := 0
+
+	for len(p) > 0 {
+		// full segment is flushed only when more data arrives, so the last one is always sealed by Close
+		if len(writer.buf) == cap(writer.buf) {
+			if err := writer.flush(streamMiddleSegment); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(writer.buf[len(writer.buf):cap(writer.buf)], p)
+		writer.buf = writer.buf[:len(writer.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close implements io.Closer, it will write final segment
+func (writer *encryptWriter) Close() error {
+	if writer.closed {
+		return nil
+	}
+
+	writer.closed = true
+
+	return writer.flush(streamLastSegment)
+}
+
+// flush is helper function to seal and write buffered segment
+func (writer *encryptWriter) flush(last byte) error {
+	if writer.counter == ^uint32(0) {
+		return errors.New("encrypted stream too long")
+	}
+
+	nonce := streamNonce(writer.header, writer.counter, last)
+
+	writer.sealed = writer.gcm.Seal(writer.sealed[:0], nonce, writer.buf, writer.header)
+	if _, err := writer.w.Write(writer.sealed); err != nil {
+		return err
+	}
+
+	writer.counter++
+	writer.buf = writer.buf[:0]
+
+	return nil
+}
+
+// NewDecryptReader will return reader which decrypts stream produced by NewEncryptWriter from r
+// Read returns ErrInvalidStream if stream was modified or truncated
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	// key is validated before anything is read
+	if _, err := newGCM(key); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidStream
+	}
+
+	if header[0] != streamVersion {
+		return nil, errors.New("unsupported encrypted stream version")
+	}
+
+	segmentSize := binary.BigEndian.Uint32(header[1:5])
+	if segmentSize == 0 || segmentSize > streamMaxSegment {
+		return nil, ErrInvalidStream
+	}
+
+	gcm, err := newStreamGCM(key, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:       bufio.NewReader(r),
+		gcm:     gcm,
+		header:  header,
+		segment: make([]byte, int(segmentSize)+gcm.Overhead()),
+	}, nil
+}
+
+// Read implements io.Reader
+func (reader *decryptReader) Read(p []byte) (int, error) {
+	for len(reader.plain) == 0 {
+		if reader.done {
+			return 0, io.EOF
+		}
+
+		if err := reader.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, reader.plain)
+	reader.plain = reader.plain[n:]
+
+	return n, nil
+}
+
+// next is helper function to read and open next segment
+func (reader *decryptReader) next() error {
+	n, err := io.ReadFull(reader.r, reader.segment)
+
+	last := streamMiddleSegment
+
+	switch err {
+	case nil:
+		// full segment, it is the last one only if nothing follows
+		if _, err := reader.r.Peek(1); err == io.EOF {
+			last = streamLastSegment
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = streamLastSegment
+	case io.EOF:
+		return ErrInvalidStream
+	default:
+		return err
+	}
+
+	nonce := streamNonce(reader.header, reader.counter, last)
+
+	plain, err := reader.gcm.Open(reader.segment[:0], nonce, reader.segment[:n], reader.header)
+	if err != nil {
+		return ErrInvalidStream
+	}
+
+	reader.plain = plain
+	reader.counter++
+	reader.done = last == streamLastSegment
+
+	return nil
+}
+
+// newStreamGCM is helper function to create AES GCM with subkey derived from key and header salt
+func newStreamGCM(key, header []byte) (cipher.AEAD, error) {
+	if _, err := newGCM(key); err != nil {
+		return nil, err
+	}
+
+	salt := header[5 : 5+streamSaltSize]
+
+	subkey := make([]byte, len(key))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, []byte(streamKeyInfo)), subkey); err != nil {
+		return nil, err
+	}
+
+	return newGCM(subkey)
+}
+
+// streamNonce is helper function to construct segment nonce
+func streamNonce(header []byte, counter uint32, last byte) []byte {
+	nonce := make([]byte, streamNoncePrefix+5)
+
+	copy(nonce, header[5+streamSaltSize:])
+	binary.BigEndian.PutUint32(nonce[streamNoncePrefix:], counter)
+	nonce[len(nonce)-1] = last
+
+	return nonce
+}
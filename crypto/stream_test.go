@@ -0,0 +1,143 @@
+package crypto_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/semirm-dev/godev/crypto"
+)
+
+func TestStreamEncryptDecrypt(t *testing.T) {
+	sizes := []int{0, 1, 1024, 64 * 1024, 64*1024 + 1, 3*64*1024 - 7, 3 * 64 * 1024}
+
+	for _, size := range sizes {
+		payload := bytes.Repeat([]byte("a"), size)
+
+		encrypted := encryptStream(t, payload)
+
+		decrypted, err := decryptStream(encrypted)
+
+		assert.NoError(t, err, size)
+		assert.Equal(t, payload, decrypted, size)
+	}
+}
+
+func TestStreamSmallWrites(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 20000)
+
+	var encrypted bytes.Buffer
+
+	writer, err := crypto.NewEncryptWriter(&encrypted, []byte(secret))
+	assert.NoError(t, err)
+
+	for i := 0; i < len(payload); i += 333 {
+		end := i + 333
+		if end > len(payload) {
+			end = len(payload)
+		}
+
+		_, err := writer.Write(payload[i:end])
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, writer.Close())
+
+	_, err = writer.Write([]byte("after close"))
+	assert.Equal(t, crypto.ErrStreamClosed, err)
+
+	decrypted, err := decryptStream(encrypted.Bytes())
+
+	assert.NoError(t, err)
+	assert.Equal(t, payload, decrypted)
+}
+
+func TestStreamTruncated(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 2*64*1024+100)
+
+	encrypted := encryptStream(t, payload)
+
+	segment := 64*1024 + 16
+	header := 44
+
+	cases := [][]byte{
+		encrypted[:header],
+		encrypted[:header+segment],
+		encrypted[:header+2*segment],
+		encrypted[:len(encrypted)-1],
+	}
+
+	for _, c := range cases {
+		_, err := decryptStream(c)
+
+		assert.Equal(t, crypto.ErrInvalidStream, err)
+	}
+}
+
+func TestStreamTampered(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 2*64*1024+100)
+
+	encrypted := encryptStream(t, payload)
+	encrypted[100] ^= 1
+
+	_, err := decryptStream(encrypted)
+
+	assert.Equal(t, crypto.ErrInvalidStream, err)
+}
+
+func TestStreamSubkeySalt(t *testing.T) {
+	first := encryptStream(t, []byte(message))
+	second := encryptStream(t, []byte(message))
+
+	// each stream has its own random salt, so its own subkey
+	assert.NotEqual(t, first[5:37], second[5:37])
+
+	// salt is authenticated, modified salt derives different subkey
+	first[5] ^= 1
+
+	_, err := decryptStream(first)
+
+	assert.Equal(t, crypto.ErrInvalidStream, err)
+}
+
+func TestStreamWrongKey(t *testing.T) {
+	encrypted := encryptStream(t, []byte(message))
+
+	reader, err := crypto.NewDecryptReader(bytes.NewReader(encrypted), []byte("kYp3s6v9y$B&E)H+MbQeThWmZq4t7w!x"))
+	assert.NoError(t, err)
+
+	_, err = ioutil.ReadAll(reader)
+
+	assert.Equal(t, crypto.ErrInvalidStream, err)
+}
+
+func TestStreamMissingSecret(t *testing.T) {
+	_, err := crypto.NewEncryptWriter(ioutil.Discard, nil)
+
+	assert.Equal(t, crypto.ErrMissingSecret, err)
+}
+
+func encryptStream(t *testing.T, payload []byte) []byte {
+	var encrypted bytes.Buffer
+
+	writer, err := crypto.NewEncryptWriter(&encrypted, []byte(secret))
+	assert.NoError(t, err)
+
+	_, err = io.Copy(writer, bytes.NewReader(payload))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return encrypted.Bytes()
+}
+
+func decryptStream(encrypted []byte) ([]byte, error) {
+	reader, err := crypto.NewDecryptReader(bytes.NewReader(encrypted), []byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,211 @@
+package crypto
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// oidX25519 is X25519 algorithm identifier (RFC 8410)
+var oidX25519 = asn1.ObjectIdentifier{1, 3, 101, 110}
+
+// ErrInvalidKey error
+var ErrInvalidKey = errors.New("invalid key")
+
+// X25519PublicKey for key agreement
+type X25519PublicKey []byte
+
+// X25519PrivateKey for key agreement
+type X25519PrivateKey []byte
+
+// x25519JWK is X25519 key in JWK format (RFC 8037)
+type x25519JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+}
+
+// pkixPublicKey is ASN.1 structure of PKIX, ASN.1 DER public key
+type pkixPublicKey struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// pkcs8PrivateKey is ASN.1 structure of PKCS #8, ASN.1 DER private key
+type pkcs8PrivateKey struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// GenerateX25519Key will generate random X25519 private key
+func GenerateX25519Key() (X25519PrivateKey, error) {
+	key, err := GenerateSalt(curve25519.ScalarSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return X25519PrivateKey(key), nil
+}
+
+// Public will return public key of private key
+func (priv X25519PrivateKey) Public() (X25519PublicKey, error) {
+	if len(priv) != curve25519.ScalarSize {
+		return nil, ErrInvalidKey
+	}
+
+	pub, err := curve25519.X25519(priv, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return X25519PublicKey(pub), nil
+}
+
+// MarshalPEM will encode private key as PKCS #8 PEM block
+func (priv X25519PrivateKey) MarshalPEM() ([]byte, error) {
+	if len(priv) != curve25519.ScalarSize {
+		return nil, ErrInvalidKey
+	}
+
+	curvePrivateKey, err := asn1.Marshal([]byte(priv))
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := asn1.Marshal(pkcs8PrivateKey{
+		Algorithm:  pkix.AlgorithmIdentifier{Algorithm: oidX25519},
+		PrivateKey: curvePrivateKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalJWK will encode private key, together with its public key, as JWK
+func (priv X25519PrivateKey) MarshalJWK() ([]byte, error) {
+	pub, err := priv.Public()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&x25519JWK{
+		Kty: "OKP",
+		Crv: "X25519",
+		X:   base64.RawURLEncoding.EncodeToString(pub),
+		D:   base64.RawURLEncoding.EncodeToString(priv),
+	})
+}
+
+// MarshalPEM will encode public key as PKIX PEM block
+func (pub X25519PublicKey) MarshalPEM() ([]byte, error) {
+	if len(pub) != curve25519.PointSize {
+		return nil, ErrInvalidKey
+	}
+
+	der, err := asn1.Marshal(pkixPublicKey{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidX25519},
+		PublicKey: asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// MarshalJWK will encode public key as JWK
+func (pub X25519PublicKey) MarshalJWK() ([]byte, error) {
+	if len(pub) != curve25519.PointSize {
+		return nil, ErrInvalidKey
+	}
+
+	return json.Marshal(&x25519JWK{
+		Kty: "OKP",
+		Crv: "X25519",
+		X:   base64.RawURLEncoding.EncodeToString(pub),
+	})
+}
+
+// ParseX25519PrivateKeyPEM will decode PKCS #8 PEM encoded X25519 private key
+func ParseX25519PrivateKeyPEM(data []byte) (X25519PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, ErrInvalidKey
+	}
+
+	key := &pkcs8PrivateKey{}
+	if rest, err := asn1.Unmarshal(block.Bytes, key); err != nil || len(rest) > 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if !key.Algorithm.Algorithm.Equal(oidX25519) {
+		return nil, ErrInvalidKey
+	}
+
+	var priv []byte
+	if rest, err := asn1.Unmarshal(key.PrivateKey, &priv); err != nil || len(rest) > 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if len(priv) != curve25519.ScalarSize {
+		return nil, ErrInvalidKey
+	}
+
+	return X25519PrivateKey(priv), nil
+}
+
+// ParseX25519PublicKeyPEM will decode PKIX PEM encoded X25519 public key
+func ParseX25519PublicKeyPEM(data []byte) (X25519PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidKey
+	}
+
+	key := &pkixPublicKey{}
+	if rest, err := asn1.Unmarshal(block.Bytes, key); err != nil || len(rest) > 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if !key.Algorithm.Algorithm.Equal(oidX25519) || len(key.PublicKey.Bytes) != curve25519.PointSize {
+		return nil, ErrInvalidKey
+	}
+
+	return X25519PublicKey(key.PublicKey.Bytes), nil
+}
+
+// ParseX25519JWK will decode X25519 JWK, private key is nil if JWK contains only public key
+func ParseX25519JWK(data []byte) (X25519PublicKey, X25519PrivateKey, error) {
+	jwk := &x25519JWK{}
+	if err := json.Unmarshal(data, jwk); err != nil {
+		return nil, nil, err
+	}
+
+	if jwk.Kty != "OKP" || jwk.Crv != "X25519" {
+		return nil, nil, ErrInvalidKey
+	}
+
+	pub, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil || len(pub) != curve25519.PointSize {
+		return nil, nil, ErrInvalidKey
+	}
+
+	if jwk.D == "" {
+		return X25519PublicKey(pub), nil, nil
+	}
+
+	priv, err := base64.RawURLEncoding.DecodeString(jwk.D)
+	if err != nil || len(priv) != curve25519.ScalarSize {
+		return nil, nil, ErrInvalidKey
+	}
+
+	return X25519PublicKey(pub), X25519PrivateKey(priv), nil
+}
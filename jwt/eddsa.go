@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+)
+
+// SigningMethodEd25519 implements EdDSA signing method (RFC 8037) with Ed25519 keys
+// Sign expects ed25519.PrivateKey, Verify ed25519.PublicKey
+type SigningMethodEd25519 struct{}
+
+// SigningMethodEdDSA is registered EdDSA signing method
+var SigningMethodEdDSA = &SigningMethodEd25519{}
+
+// ErrInvalidKeyType error
+var ErrInvalidKeyType = errors.New("key is of invalid type")
+
+func init() {
+	jwtLib.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwtLib.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+// Alg implements jwtLib.SigningMethod.Alg
+func (m *SigningMethodEd25519) Alg() string {
+	return EdDSA
+}
+
+// Sign implements jwtLib.SigningMethod.Sign
+func (m *SigningMethodEd25519) Sign(signingString string, key interface{}) (string, error) {
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok || len(priv) != ed25519.PrivateKeySize {
+		return "", ErrInvalidKeyType
+	}
+
+	return jwtLib.EncodeSegment(ed25519.Sign(priv, []byte(signingString))), nil
+}
+
+// Verify implements jwtLib.SigningMethod.Verify
+func (m *SigningMethodEd25519) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok || len(pub) != ed25519.PublicKeySize {
+		return ErrInvalidKeyType
+	}
+
+	sig, err := jwtLib.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, []byte(signingString), sig) {
+		return jwtLib.ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 8037, appendix A.4
+const (
+	eddsaSeed          = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+	eddsaSigningString = "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"
+	eddsaSignature     = "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+)
+
+func TestEdDSAKnownAnswer(t *testing.T) {
+	seed, err := hex.DecodeString(eddsaSeed)
+	assert.NoError(t, err)
+
+	priv := ed25519.NewKeyFromSeed(seed)
+
+	method := jwtLib.GetSigningMethod(jwt.EdDSA)
+	assert.Equal(t, jwt.SigningMethodEdDSA, method)
+
+	signature, err := method.Sign(eddsaSigningString, priv)
+
+	assert.NoError(t, err)
+	assert.Equal(t, eddsaSignature, signature)
+
+	assert.NoError(t, method.Verify(eddsaSigningString, signature, priv.Public()))
+	assert.Equal(t, jwtLib.ErrSignatureInvalid, method.Verify(eddsaSigningString+"x", signature, priv.Public()))
+	assert.Equal(t, jwt.ErrInvalidKeyType, method.Verify(eddsaSigningString, signature, []byte("secret")))
+
+	_, err = method.Sign(eddsaSigningString, []byte("secret"))
+	assert.Equal(t, jwt.ErrInvalidKeyType, err)
+}
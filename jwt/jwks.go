@@ -0,0 +1,452 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksTimeout is default timeout of JWKS fetch
+const jwksTimeout = 10 * time.Second
+
+// jwkMinRSABits is minimal RSA modulus size of JWK
+const jwkMinRSABits = 2048
+
+// ErrUnsupportedJWK error
+var ErrUnsupportedJWK = errors.New("unsupported jwk")
+
+// ErrWeakJWK error
+var ErrWeakJWK = errors.New("jwk rsa modulus must be at least 2048 bits")
+
+// jwksHTTPClient is default client of JWKSClient, http.DefaultClient has no timeout
+var jwksHTTPClient = &http.Client{Timeout: jwksTimeout}
+
+// JWK is public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is JSON Web Key Set
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWKSClient is KeySet fetching keys from remote JWKS URL
+// Keys are cached for TTL, unknown kid triggers refetch at most once per MinRefetchInterval,
+// expired keys are refetched at most once per MinRefetchInterval too and used until fetch succeeds
+type JWKSClient struct {
+	URL string
+	// Client used for requests, client with 10 seconds timeout if not set
+	Client *http.Client
+	// TTL of fetched keys, 1 hour if not set
+	TTL time.Duration
+	// MinRefetchInterval limits refetching on unknown kid and expired keys, 1 minute if not set
+	MinRefetchInterval time.Duration
+	// Timeout of fetch triggered by Key, 10 seconds if not set
+	Timeout time.Duration
+	// Now returns current time, time.Now if not set
+	Now func() time.Time
+
+	mu        sync.RWMutex
+	keys      map[string]*Key
+	fetchedAt time.Time
+	// attemptedAt is time of last fetch, successful or not
+	attemptedAt time.Time
+	// err of last fetch
+	err error
+	// fetch serializes fetches
+	fetch sync.Mutex
+}
+
+// NewJWK will return JWK of public part of key, HMAC keys are not supported
+func NewJWK(key *Key) (*JWK, error) {
+	jwk := &JWK{
+		Use: "sig",
+		Kid: key.ID,
+		Alg: key.Algorithm,
+	}
+
+	switch k := publicKey(key.Key).(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeJWKValue(k.N.Bytes())
+		jwk.E = encodeJWKValue(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedJWK
+		}
+
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = encodeJWKValue(padBytes(k.X.Bytes(), 32))
+		jwk.Y = encodeJWKValue(padBytes(k.Y.Bytes(), 32))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeJWKValue(k)
+	default:
+		return nil, ErrUnsupportedJWK
+	}
+
+	return jwk, nil
+}
+
+// Key will return verification Key of JWK
+// If alg is missing, RS256 is used for RSA, ES256 for EC and EdDSA for OKP keys
+// RSA keys with modulus under 2048 bits are rejected with ErrWeakJWK
+func (jwk *JWK) Key() (*Key, error) {
+	key := &Key{
+		ID:        jwk.Kid,
+		Algorithm: jwk.Alg,
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, errN := decodeJWKValue(jwk.N)
+		e, errE := decodeJWKValue(jwk.E)
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedJWK
+		}
+
+		modulus := new(big.Int).SetBytes(n)
+		if modulus.BitLen() < jwkMinRSABits {
+			return nil, ErrWeakJWK
+		}
+
+		key.Key = &rsa.PublicKey{
+			N: modulus,
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+		if key.Algorithm == "" {
+			key.Algorithm = RS256
+		}
+	case "EC":
+		x, errX := decodeJWKValue(jwk.X)
+		y, errY := decodeJWKValue(jwk.Y)
+		if jwk.Crv != "P-256" || errX != nil || errY != nil {
+			return nil, ErrUnsupportedJWK
+		}
+
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrUnsupportedJWK
+		}
+
+		key.Key = pub
+
+		if key.Algorithm == "" {
+			key.Algorithm = ES256
+		}
+	case "OKP":
+		x, err := decodeJWKValue(jwk.X)
+		if jwk.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedJWK
+		}
+
+		key.Key = ed25519.PublicKey(x)
+
+		if key.Algorithm == "" {
+			key.Algorithm = EdDSA
+		}
+	default:
+		return nil, ErrUnsupportedJWK
+	}
+
+	return key, nil
+}
+
+// NewJWKSHandler will return http.Handler serving public parts of keys as JWKS document
+// Keys should include both current and previous (rotated) keys, until tokens signed with them expire
+func NewJWKSHandler(keys ...*Key) (http.Handler, error) {
+	jwks := &JWKS{
+		Keys: make([]*JWK, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		jwk, err := NewJWK(key)
+		if err != nil {
+			return nil, err
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	body, err := json.Marshal(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(body)
+	}), nil
+}
+
+// NewJWKSClient will initialize JWKSClient with default TTL and refetch interval
+func NewJWKSClient(url string) *JWKSClient {
+	return &JWKSClient{
+		URL:                url,
+		TTL:                time.Hour,
+		MinRefetchInterval: time.Minute,
+	}
+}
+
+// Key implements KeySet.Key
+// Keys are fetched if not fetched yet or expired, unknown kid triggers refetch,
+// fetches are attempted at most once per MinRefetchInterval and expired keys are used while fetch fails
+func (client *JWKSClient) Key(kid string) (*Key, error) {
+	if client.expired() {
+		if err := client.refresh(); err != nil && !client.fetched() {
+			return nil, err
+		}
+	}
+
+	if key, ok := client.cached(kid); ok {
+		return key, nil
+	}
+
+	if err := client.refresh(); err != nil {
+		return nil, err
+	}
+
+	if key, ok := client.cached(kid); ok {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// Refresh will fetch keys from URL, cached keys are kept if fetch fails
+func (client *JWKSClient) Refresh(ctx context.Context) error {
+	client.fetch.Lock()
+	defer client.fetch.Unlock()
+
+	return client.refreshLocked(ctx)
+}
+
+// StartRefresh will refresh keys in background every interval, until ctx is done
+// If interval is not positive, keys are refreshed every TTL
+// Refresh errors are ignored, cached keys are kept until next successful refresh
+func (client *JWKSClient) StartRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = client.ttl()
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				client.Refresh(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// refresh is helper function to fetch keys unless fetch was attempted within MinRefetchInterval,
+// in which case error of last fetch is returned
+// Attempt time is checked under fetch lock, so concurrent callers wait for single fetch instead of repeating it
+func (client *JWKSClient) refresh() error {
+	client.fetch.Lock()
+	defer client.fetch.Unlock()
+
+	client.mu.RLock()
+	due := client.attemptedAt.IsZero() || client.now().Sub(client.attemptedAt) >= client.minRefetchInterval()
+	err := client.err
+	client.mu.RUnlock()
+
+	if !due {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), client.timeout())
+	defer cancel()
+
+	return client.refreshLocked(ctx)
+}
+
+// refreshLocked is helper function to fetch keys, fetch lock must be held
+func (client *JWKSClient) refreshLocked(ctx context.Context) error {
+	client.mu.Lock()
+	client.attemptedAt = client.now()
+	client.mu.Unlock()
+
+	keys, err := client.fetchKeys(ctx)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.err = err
+
+	if err != nil {
+		return err
+	}
+
+	client.keys = keys
+	client.fetchedAt = client.now()
+
+	return nil
+}
+
+// fetchKeys is helper function to fetch and parse JWKS, unsupported keys are skipped
+func (client *JWKSClient) fetchKeys(ctx context.Context) (map[string]*Key, error) {
+	httpClient := client.Client
+	if httpClient == nil {
+		httpClient = jwksHTTPClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks responded with status %d", resp.StatusCode)
+	}
+
+	jwks := &JWKS{}
+	if err := json.NewDecoder(resp.Body).Decode(jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*Key, len(jwks.Keys))
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.Key()
+		if err != nil {
+			continue
+		}
+
+		keys[key.ID] = key
+	}
+
+	return keys, nil
+}
+
+// cached is helper function to get cached key
+func (client *JWKSClient) cached(kid string) (*Key, bool) {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	key, ok := client.keys[kid]
+
+	return key, ok
+}
+
+// fetched is helper function to check if keys were ever fetched
+func (client *JWKSClient) fetched() bool {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	return !client.fetchedAt.IsZero()
+}
+
+// expired is helper function to check if keys need to be fetched
+func (client *JWKSClient) expired() bool {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	return client.fetchedAt.IsZero() || client.now().Sub(client.fetchedAt) >= client.ttl()
+}
+
+// ttl is helper function to get TTL or default
+func (client *JWKSClient) ttl() time.Duration {
+	if client.TTL <= 0 {
+		return time.Hour
+	}
+
+	return client.TTL
+}
+
+// timeout is helper function to get Timeout or default
+func (client *JWKSClient) timeout() time.Duration {
+	if client.Timeout <= 0 {
+		return jwksTimeout
+	}
+
+	return client.Timeout
+}
+
+// minRefetchInterval is helper function to get MinRefetchInterval or default
+func (client *JWKSClient) minRefetchInterval() time.Duration {
+	if client.MinRefetchInterval <= 0 {
+		return time.Minute
+	}
+
+	return client.MinRefetchInterval
+}
+
+// now is helper function to get current time
+func (client *JWKSClient) now() time.Time {
+	if client.Now != nil {
+		return client.Now()
+	}
+
+	return time.Now()
+}
+
+// encodeJWKValue is helper function to base64 URL encode JWK value without padding
+func encodeJWKValue(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeJWKValue is helper function to decode base64 URL encoded JWK value without padding
+func decodeJWKValue(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// padBytes is helper function to left pad b with zeros to size
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+
+	return padded
+}
@@ -0,0 +1,407 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWK(t *testing.T) {
+	for _, key := range testKeys(t)[3:] {
+		jwk, err := jwt.NewJWK(key)
+		assert.NoError(t, err, key.Algorithm)
+
+		assert.Equal(t, key.ID, jwk.Kid)
+		assert.Equal(t, key.Algorithm, jwk.Alg)
+		assert.Equal(t, "sig", jwk.Use)
+
+		parsed, err := jwk.Key()
+
+		assert.NoError(t, err)
+		assert.Equal(t, key.Public(), parsed)
+	}
+
+	_, err := jwt.NewJWK(&jwt.Key{ID: "hs256", Algorithm: jwt.HS256, Key: secret})
+	assert.Equal(t, jwt.ErrUnsupportedJWK, err)
+}
+
+// RFC 8037, appendix A.2
+func TestJWKEd25519KnownAnswer(t *testing.T) {
+	seed, err := hex.DecodeString(eddsaSeed)
+	assert.NoError(t, err)
+
+	jwk, err := jwt.NewJWK(&jwt.Key{Algorithm: jwt.EdDSA, Key: ed25519.NewKeyFromSeed(seed)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.Equal(t, "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo", jwk.X)
+}
+
+func TestJWKInvalid(t *testing.T) {
+	invalid := []*jwt.JWK{
+		{Kty: "oct"},
+		{Kty: "RSA", N: "!", E: "AQAB"},
+		{Kty: "EC", Crv: "P-384", X: "AA", Y: "AA"},
+		{Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"},
+		{Kty: "OKP", Crv: "Ed25519", X: "AQ"},
+	}
+
+	for _, jwk := range invalid {
+		_, err := jwk.Key()
+		assert.Equal(t, jwt.ErrUnsupportedJWK, err, jwk.Kty)
+	}
+}
+
+func TestJWKWeakRSA(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	jwk, err := jwt.NewJWK(&jwt.Key{ID: "rs256", Algorithm: jwt.RS256, Key: &rsaKey.PublicKey})
+	assert.NoError(t, err)
+
+	_, err = jwk.Key()
+	assert.Equal(t, jwt.ErrWeakJWK, err)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	keys := testKeys(t)[3:]
+
+	handler, err := jwt.NewJWKSHandler(keys...)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	jwks := &jwt.JWKS{}
+
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), jwks))
+	assert.Len(t, jwks.Keys, len(keys))
+
+	// private parts are never published
+	assert.NotContains(t, rec.Body.String(), `"d"`)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	_, err = jwt.NewJWKSHandler(testKeys(t)[0])
+	assert.Equal(t, jwt.ErrUnsupportedJWK, err)
+}
+
+// jwksServer serves JWKS of keys, keys can be replaced and requests are counted
+type jwksServer struct {
+	*httptest.Server
+	handler  atomic.Value
+	requests int32
+}
+
+func newJWKSServer(t *testing.T, keys ...*jwt.Key) *jwksServer {
+	server := &jwksServer{}
+	server.setKeys(t, keys...)
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.requests, 1)
+		server.handler.Load().(http.Handler).ServeHTTP(w, r)
+	}))
+
+	return server
+}
+
+func (server *jwksServer) setKeys(t *testing.T, keys ...*jwt.Key) {
+	handler, err := jwt.NewJWKSHandler(keys...)
+	assert.NoError(t, err)
+
+	server.handler.Store(handler)
+}
+
+func (server *jwksServer) count() int32 {
+	return atomic.LoadInt32(&server.requests)
+}
+
+func TestJWKSClient(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5], keys[6])
+	defer server.Close()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Client = server.Client()
+
+	verifier := &jwt.Token{
+		KeySet: client,
+	}
+
+	for _, key := range keys[5:] {
+		token := &jwt.Token{
+			SigningKey: key,
+		}
+
+		assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour, Fields: fields}))
+
+		claims, valid := verifier.ValidateAndExtract(token.Content)
+
+		assert.True(t, valid, key.Algorithm)
+		assert.Equal(t, fields, claims.Fields)
+	}
+
+	// keys are cached
+	assert.Equal(t, int32(1), server.count())
+}
+
+func TestJWKSClientTTL(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	now := time.Now()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.TTL = time.Minute
+	client.Now = func() time.Time {
+		return now
+	}
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+
+	_, err = client.Key("es256")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), server.count())
+
+	now = now.Add(time.Minute)
+
+	_, err = client.Key("es256")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.count())
+}
+
+func TestJWKSClientUnknownKid(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	now := time.Now()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Now = func() time.Time {
+		return now
+	}
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+
+	// rotated key is not refetched more than once per MinRefetchInterval
+	server.setKeys(t, keys[5], keys[6])
+
+	_, err = client.Key("eddsa")
+	assert.Equal(t, jwt.ErrUnknownKey, err)
+
+	now = now.Add(time.Minute)
+
+	_, err = client.Key("unknown")
+	assert.Equal(t, jwt.ErrUnknownKey, err)
+	assert.Equal(t, int32(2), server.count())
+
+	_, err = client.Key("unknown")
+	assert.Equal(t, jwt.ErrUnknownKey, err)
+	assert.Equal(t, int32(2), server.count())
+
+	key, err := client.Key("eddsa")
+
+	assert.NoError(t, err)
+	assert.Equal(t, keys[6].Public(), key)
+}
+
+func TestJWKSClientFetchError(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	now := time.Now()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Now = func() time.Time {
+		return now
+	}
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+
+	server.Close()
+	now = now.Add(2 * time.Hour)
+
+	// cached keys are kept when refresh fails
+	_, err = client.Key("es256")
+	assert.NoError(t, err)
+
+	unreachable := jwt.NewJWKSClient(server.URL)
+
+	_, err = unreachable.Key("es256")
+	assert.Error(t, err)
+}
+
+func TestJWKSClientOutageBackoff(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	now := time.Now()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Now = func() time.Time {
+		return now
+	}
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+
+	server.handler.Store(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	now = now.Add(2 * time.Hour)
+
+	// expired keys are served, failing endpoint is not requested on every call
+	for i := 0; i < 5; i++ {
+		key, err := client.Key("es256")
+
+		assert.NoError(t, err)
+		assert.Equal(t, keys[5].Public(), key)
+
+		_, err = client.Key("unknown")
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, int32(2), server.count())
+
+	now = now.Add(time.Minute)
+
+	_, err = client.Key("es256")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), server.count())
+}
+
+func TestJWKSClientConcurrentUnknownKid(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	now := time.Now()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Now = func() time.Time {
+		return now
+	}
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := client.Key(fmt.Sprintf("random-%d", i))
+			assert.Equal(t, jwt.ErrUnknownKey, err)
+		}(i)
+	}
+
+	wg.Wait()
+
+	// only first unknown kid triggers refetch, others wait for it and are limited by MinRefetchInterval
+	assert.Equal(t, int32(2), server.count())
+}
+
+func TestJWKSClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+
+	_, err := client.Key("es256")
+
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestJWKSClientStartRefresh(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	client := jwt.NewJWKSClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.StartRefresh(ctx, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return server.count() >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+
+	count := server.count()
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, count, server.count())
+
+	_, err := client.Key("es256")
+	assert.NoError(t, err)
+}
+
+func TestJWKSClientStartRefreshDefaultInterval(t *testing.T) {
+	keys := testKeys(t)
+
+	server := newJWKSServer(t, keys[5])
+	defer server.Close()
+
+	client := jwt.NewJWKSClient(server.URL)
+	client.TTL = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client.StartRefresh(ctx, 0)
+
+	assert.Eventually(t, func() bool {
+		return server.count() >= 2
+	}, time.Second, 5*time.Millisecond)
+}
@@ -5,35 +5,91 @@ import (
 	"time"
 
 	jwtLib "github.com/dgrijalva/jwt-go"
+
+	"github.com/semirm-dev/godev/str"
 )
 
 // ErrMissingSecret error
 var ErrMissingSecret = errors.New("missing secret key")
 
 // Token wrapper
+// Tokens are signed with SigningKey, or with Secret using HS256 if SigningKey is not set
+// Tokens are verified with key from KeySet found by kid header, or with SigningKey or Secret if KeySet is not set
 type Token struct {
-	Secret  []byte
-	Content string
+	Secret     []byte
+	Content    string
+	SigningKey *Key
+	KeySet     KeySet
+	// Algorithms allowed in validation, token algorithm must match its key algorithm in any case
+	Algorithms []string
+	// Revocations are consulted in validation if set
+	Revocations RevocationStore
+	// Issuer is set as iss of generated tokens and required in validation if set
+	Issuer string
+	// Audience is set as aud of generated tokens and required in validation if set
+	Audience string
+	// Leeway allowed in exp, nbf and iat validation for clock skew between servers
+	Leeway time.Duration
+	// Now returns current time, time.Now if not set
+	Now func() time.Time
 }
 
 // Claims for token
 type Claims struct {
 	Expiration time.Duration
 	Fields     map[string]interface{}
+	// Audiences of parsed token, aud may be string or array (RFC 7519), Audience is set only if aud has single value
+	Audiences []string `json:"-"`
 	jwtLib.StandardClaims
 }
 
 // Generate JWT token for given claims
+// jti is set to new UUIDv7 if claims have no Id, iat is set to current time,
+// iss and aud are set to Issuer and Audience if claims have none
 func (token *Token) Generate(claims *Claims) error {
-	if token.Secret == nil || len(token.Secret) == 0 {
-		return ErrMissingSecret
+	key := token.SigningKey
+	if key == nil {
+		if token.Secret == nil || len(token.Secret) == 0 {
+			return ErrMissingSecret
+		}
+
+		key = &Key{Algorithm: HS256, Key: token.Secret}
 	}
 
-	claims.StandardClaims.ExpiresAt = time.Now().Add(claims.Expiration).Unix()
+	if !contains(algorithms, key.Algorithm) {
+		return ErrAlgorithmNotAllowed
+	}
+
+	method := signingMethod(key.Algorithm)
 
-	tokenLib := jwtLib.NewWithClaims(jwtLib.SigningMethodHS256, claims)
+	if claims.Id == "" {
+		id, err := str.NewUUIDv7()
+		if err != nil {
+			return err
+		}
 
-	tokenStr, err := tokenLib.SignedString(token.Secret)
+		claims.Id = id.String()
+	}
+
+	if claims.Issuer == "" {
+		claims.Issuer = token.Issuer
+	}
+
+	if claims.Audience == "" {
+		claims.Audience = token.Audience
+	}
+
+	now := token.now()
+
+	claims.StandardClaims.IssuedAt = now.Unix()
+	claims.StandardClaims.ExpiresAt = now.Add(claims.Expiration).Unix()
+
+	tokenLib := jwtLib.NewWithClaims(method, claims)
+	if key.ID != "" {
+		tokenLib.Header["kid"] = key.ID
+	}
+
+	tokenStr, err := tokenLib.SignedString(key.Key)
 	if err != nil {
 		return err
 	}
@@ -44,36 +100,98 @@ func (token *Token) Generate(claims *Claims) error {
 }
 
 // ValidateAndExtract will check if given JWT token is valid and return claims
+// Use Validate to find out why token is not valid
 func (token *Token) ValidateAndExtract(tokenStr string) (*Claims, bool) {
-	claims := &Claims{}
+	claims, err := token.Validate(tokenStr)
+
+	return claims, err == nil
+}
 
-	tokenLib, err := token.parse(tokenStr, claims)
+// Validate will check if given JWT token is valid and return claims, claims are returned even if token is not valid
+// Refresh tokens issued by RefreshManager are not valid, even if signed with the same key
+// Returned error is *ValidationError, or error of key lookup (e.g. ErrUnknownKey) or Revocations store
+func (token *Token) Validate(tokenStr string) (*Claims, error) {
+	claims, err := token.verify(tokenStr)
 	if err != nil {
-		return claims, false
+		return claims, err
+	}
+
+	if claims.Fields[refreshTypeField] == refreshType {
+		return claims, &ValidationError{Err: ErrInvalidTokenType, Reason: refreshType}
 	}
 
-	return claims, token.valid(tokenLib, claims)
+	return claims, token.checkRevoked(claims)
 }
 
-// parse is helper function to parse jwt
-func (token *Token) parse(tokenStr string, claims *Claims) (*jwtLib.Token, error) {
-	tokenLib, err := jwtLib.ParseWithClaims(tokenStr, claims, func(t *jwtLib.Token) (interface{}, error) {
+// verify is helper function to parse jwt and check its signature and registered claims
+func (token *Token) verify(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+
+	// claims are validated below with token clock and leeway
+	parser := &jwtLib.Parser{SkipClaimsValidation: true}
+
+	if _, err := parser.ParseWithClaims(tokenStr, claims, token.verificationKey); err != nil {
+		return claims, parseError(err)
+	}
+
+	return claims, claims.validate(token.now(), token.Leeway, token.Issuer, token.Audience)
+}
+
+// verificationKey is helper function to find key verifying jwt, implements jwtLib.Keyfunc
+func (token *Token) verificationKey(t *jwtLib.Token) (interface{}, error) {
+	alg := t.Method.Alg()
+
+	if len(token.Algorithms) > 0 && !contains(token.Algorithms, alg) {
+		return nil, ErrAlgorithmNotAllowed
+	}
+
+	var key *Key
+
+	switch {
+	case token.KeySet != nil:
+		kid, _ := t.Header["kid"].(string)
+
+		k, err := token.KeySet.Key(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		key = k
+	case token.SigningKey != nil:
+		key = token.SigningKey
+	case len(token.Secret) > 0:
+		// Secret verifies any HMAC algorithm
 		if _, ok := t.Method.(*jwtLib.SigningMethodHMAC); !ok {
 			return nil, errors.New("failed to parse JWT token")
 		}
 
-		return token.Secret, nil
-	})
+		key = &Key{Algorithm: alg, Key: token.Secret}
+	default:
+		return nil, ErrMissingSecret
+	}
+
+	if key.Algorithm != alg {
+		return nil, ErrAlgorithmNotAllowed
+	}
 
-	return tokenLib, err
+	return publicKey(key.Key), nil
 }
 
-// valid is helper function to validate jwt
-func (token *Token) valid(tokenLib *jwtLib.Token, claims *Claims) bool {
-	if tokenLib != nil {
-		_, claimsOk := tokenLib.Claims.(jwtLib.Claims)
+// now is helper function to get current time
+func (token *Token) now() time.Time {
+	if token.Now != nil {
+		return token.Now()
+	}
 
-		return claimsOk && tokenLib.Valid && claims.ExpiresAt > time.Now().Unix()
+	return time.Now()
+}
+
+// contains is helper function to check if values contain value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
 
 	return false
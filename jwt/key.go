@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+)
+
+// Supported signing algorithms
+const (
+	HS256 = "HS256"
+	HS384 = "HS384"
+	HS512 = "HS512"
+	RS256 = "RS256"
+	PS256 = "PS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+// algorithms supported for signing
+var algorithms = []string{HS256, HS384, HS512, RS256, PS256, ES256, EdDSA}
+
+// signingMethodPS256 signs PS256 with salt length equal to hash length (RFC 7518),
+// registered jwtLib PS256 uses automatic (max) salt length, it is kept for verification since it accepts any salt length
+var signingMethodPS256 = &jwtLib.SigningMethodRSAPSS{
+	SigningMethodRSA: jwtLib.SigningMethodPS256.SigningMethodRSA,
+	Options: &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	},
+}
+
+var (
+	// ErrUnknownKey error
+	ErrUnknownKey = errors.New("unknown key id")
+	// ErrAlgorithmNotAllowed error
+	ErrAlgorithmNotAllowed = errors.New("signing algorithm not allowed")
+)
+
+// Key for signing and verifying tokens
+// Key is []byte for HS256/384/512, *rsa.PrivateKey for RS256 and PS256, *ecdsa.PrivateKey (P-256) for ES256,
+// ed25519.PrivateKey for EdDSA, public keys are enough for verification only
+type Key struct {
+	// ID is emitted as kid header and used to look up key in KeySet
+	ID        string
+	Algorithm string
+	Key       interface{}
+}
+
+// KeySet finds verification keys by key id (kid header)
+type KeySet interface {
+	Key(kid string) (*Key, error)
+}
+
+// StaticKeySet is KeySet of keys by their ID
+type StaticKeySet map[string]*Key
+
+// NewStaticKeySet will initialize StaticKeySet with given keys
+func NewStaticKeySet(keys ...*Key) StaticKeySet {
+	set := make(StaticKeySet)
+
+	for _, key := range keys {
+		set[key.ID] = key
+	}
+
+	return set
+}
+
+// Key implements KeySet.Key
+func (set StaticKeySet) Key(kid string) (*Key, error) {
+	key, ok := set[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// Public will return key with public part of private key, HMAC keys are returned as they are
+func (key *Key) Public() *Key {
+	return &Key{
+		ID:        key.ID,
+		Algorithm: key.Algorithm,
+		Key:       publicKey(key.Key),
+	}
+}
+
+// publicKey is helper function to get public key of private key
+func publicKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	default:
+		return key
+	}
+}
+
+// signingMethod is helper function to get method used for signing with given algorithm
+func signingMethod(algorithm string) jwtLib.SigningMethod {
+	if algorithm == PS256 {
+		return signingMethodPS256
+	}
+
+	return jwtLib.GetSigningMethod(algorithm)
+}
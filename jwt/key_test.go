@@ -0,0 +1,212 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKeys(t *testing.T) []*jwt.Key {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	return []*jwt.Key{
+		{ID: "hs256", Algorithm: jwt.HS256, Key: secret},
+		{ID: "hs384", Algorithm: jwt.HS384, Key: secret},
+		{ID: "hs512", Algorithm: jwt.HS512, Key: secret},
+		{ID: "rs256", Algorithm: jwt.RS256, Key: rsaKey},
+		{ID: "ps256", Algorithm: jwt.PS256, Key: rsaKey},
+		{ID: "es256", Algorithm: jwt.ES256, Key: ecdsaKey},
+		{ID: "eddsa", Algorithm: jwt.EdDSA, Key: ed25519Key},
+	}
+}
+
+func TestSigningKeys(t *testing.T) {
+	keys := testKeys(t)
+
+	publicKeys := make([]*jwt.Key, 0, len(keys))
+	for _, key := range keys {
+		publicKeys = append(publicKeys, key.Public())
+	}
+
+	verifier := &jwt.Token{
+		KeySet: jwt.NewStaticKeySet(publicKeys...),
+	}
+
+	for _, key := range keys {
+		token := &jwt.Token{
+			SigningKey: key,
+		}
+
+		err := token.Generate(&jwt.Claims{
+			Expiration: time.Hour,
+			Fields:     fields,
+		})
+		assert.NoError(t, err, key.Algorithm)
+
+		parsed, _ := jwtLib.Parse(token.Content, nil)
+
+		assert.Equal(t, key.Algorithm, parsed.Header["alg"])
+		assert.Equal(t, key.ID, parsed.Header["kid"])
+
+		claims, valid := verifier.ValidateAndExtract(token.Content)
+
+		assert.True(t, valid, key.Algorithm)
+		assert.Equal(t, fields, claims.Fields)
+
+		// signing key verifies its own tokens
+		_, valid = token.ValidateAndExtract(token.Content)
+		assert.True(t, valid, key.Algorithm)
+	}
+}
+
+func TestAlgorithmAllowList(t *testing.T) {
+	keys := testKeys(t)
+	set := jwt.NewStaticKeySet(keys...)
+
+	verifier := &jwt.Token{
+		KeySet:     set,
+		Algorithms: []string{jwt.ES256, jwt.EdDSA},
+	}
+
+	for _, key := range keys {
+		token := &jwt.Token{
+			SigningKey: key,
+		}
+
+		assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+		_, valid := verifier.ValidateAndExtract(token.Content)
+
+		assert.Equal(t, key.Algorithm == jwt.ES256 || key.Algorithm == jwt.EdDSA, valid, key.Algorithm)
+	}
+}
+
+func TestKeyAlgorithmMismatch(t *testing.T) {
+	keys := testKeys(t)
+
+	// token signed with HS256 but claiming kid of HS512 key with the same secret
+	token := &jwt.Token{
+		SigningKey: &jwt.Key{ID: "hs512", Algorithm: jwt.HS256, Key: secret},
+	}
+
+	assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+	verifier := &jwt.Token{
+		KeySet: jwt.NewStaticKeySet(keys...),
+	}
+
+	_, valid := verifier.ValidateAndExtract(token.Content)
+	assert.False(t, valid)
+
+	// RSA public key must not be used as HMAC secret
+	rsaKey := keys[3].Public()
+
+	verifier = &jwt.Token{
+		KeySet: jwt.NewStaticKeySet(&jwt.Key{ID: "rs256", Algorithm: jwt.RS256, Key: rsaKey.Key}),
+	}
+
+	forged := &jwt.Token{
+		SigningKey: &jwt.Key{ID: "rs256", Algorithm: jwt.HS256, Key: []byte("public key bytes")},
+	}
+
+	assert.NoError(t, forged.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+	_, valid = verifier.ValidateAndExtract(forged.Content)
+	assert.False(t, valid)
+}
+
+func TestUnknownKey(t *testing.T) {
+	keys := testKeys(t)
+
+	token := &jwt.Token{
+		SigningKey: keys[5],
+	}
+
+	assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+	verifier := &jwt.Token{
+		KeySet: jwt.NewStaticKeySet(keys[6]),
+	}
+
+	_, valid := verifier.ValidateAndExtract(token.Content)
+	assert.False(t, valid)
+
+	_, err := jwt.NewStaticKeySet().Key("missing")
+	assert.Equal(t, jwt.ErrUnknownKey, err)
+}
+
+func TestSecretBackwardCompatible(t *testing.T) {
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+	assert.True(t, strings.HasPrefix(token.Content, "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9."))
+
+	_, valid := token.ValidateAndExtract(token.Content)
+	assert.True(t, valid)
+
+	keys := testKeys(t)
+
+	rsaToken := &jwt.Token{
+		SigningKey: keys[3],
+	}
+
+	assert.NoError(t, rsaToken.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+	_, valid = token.ValidateAndExtract(rsaToken.Content)
+	assert.False(t, valid)
+}
+
+func TestPS256SaltLength(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	token := &jwt.Token{
+		SigningKey: &jwt.Key{Algorithm: jwt.PS256, Key: rsaKey},
+	}
+
+	assert.NoError(t, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+
+	parts := strings.Split(token.Content, ".")
+	assert.Len(t, parts, 3)
+
+	sig, err := jwtLib.DecodeSegment(parts[2])
+	assert.NoError(t, err)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	err = rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, digest[:], sig, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+	assert.NoError(t, err)
+
+	_, valid := token.ValidateAndExtract(token.Content)
+	assert.True(t, valid)
+}
+
+func TestGenerateUnsupportedAlgorithm(t *testing.T) {
+	token := &jwt.Token{
+		SigningKey: &jwt.Key{Algorithm: "none", Key: secret},
+	}
+
+	assert.Equal(t, jwt.ErrAlgorithmNotAllowed, token.Generate(&jwt.Claims{Expiration: time.Hour}))
+}
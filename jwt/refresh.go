@@ -0,0 +1,281 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+const (
+	// refreshTokenBits is entropy of opaque refresh tokens
+	refreshTokenBits = 256
+	// refreshFamilyField is claim holding token family of JWT refresh tokens
+	refreshFamilyField = "fam"
+	// refreshTypeField is claim marking JWT as refresh token, so access tokens can not be used instead
+	refreshTypeField = "typ"
+	refreshType      = "refresh"
+)
+
+var (
+	// ErrInvalidRefreshToken error
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenExpired error
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	// ErrRefreshTokenRevoked error
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	// ErrRefreshTokenReused error, returned when already rotated token is used again, its whole family is revoked
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrRefreshTokenNotFound error
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+// RefreshToken stored in RefreshStore
+// Tokens issued by rotating the same initial token belong to the same family
+type RefreshToken struct {
+	ID        string
+	FamilyID  string
+	Subject   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshStore persists refresh tokens, implementations must be safe for concurrent use
+type RefreshStore interface {
+	// Save stores new refresh token
+	Save(token *RefreshToken) error
+	// Get returns refresh token by id, ErrRefreshTokenNotFound if it does not exist
+	Get(id string) (*RefreshToken, error)
+	// MarkUsed atomically marks token as used, returns false if it was already used
+	MarkUsed(token *RefreshToken) (bool, error)
+	// RevokeFamily revokes all tokens of family until expiresAt
+	RevokeFamily(familyID string, expiresAt time.Time) error
+	// FamilyRevoked returns whether family is revoked
+	FamilyRevoked(familyID string) (bool, error)
+}
+
+// RefreshManager issues and rotates refresh tokens
+// Every refresh token can be used (rotated) only once, using it again revokes its whole family,
+// since either the legitimate user or an attacker holds a stolen token
+type RefreshManager struct {
+	Store RefreshStore
+	// TTL of refresh token, 30 days if not set
+	TTL time.Duration
+	// Token signs JWT refresh tokens, opaque random tokens are issued if not set
+	Token *Token
+	// Revocations rejects refresh tokens of subjects revoked after they were issued (e.g. by Token.RevokeSubject),
+	// usually the same store as Revocations of access tokens, subject revocation is not checked if not set
+	Revocations RevocationStore
+	// Now returns current time, time.Now if not set
+	Now func() time.Time
+}
+
+// NewRefreshManager will initialize RefreshManager issuing opaque refresh tokens valid for 30 days
+func NewRefreshManager(store RefreshStore) *RefreshManager {
+	return &RefreshManager{
+		Store: store,
+		TTL:   30 * 24 * time.Hour,
+	}
+}
+
+// Issue will return new refresh token for subject, starting new token family (e.g. on login)
+func (manager *RefreshManager) Issue(subject string) (string, error) {
+	familyID, err := str.TokenWithEntropy(128, str.AlphabetBase62)
+	if err != nil {
+		return "", err
+	}
+
+	return manager.issue(subject, familyID)
+}
+
+// Rotate will exchange refresh token for new one of the same family and return it with token subject
+// Expired, revoked (also by subject revocation), unknown and reused tokens are rejected, reuse revokes the whole family
+func (manager *RefreshManager) Rotate(refreshToken string) (string, string, error) {
+	stored, err := manager.lookup(refreshToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	first, err := manager.Store.MarkUsed(stored)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !first {
+		if err := manager.Store.RevokeFamily(stored.FamilyID, manager.familyExpiresAt()); err != nil {
+			return "", "", err
+		}
+
+		return "", "", ErrRefreshTokenReused
+	}
+
+	rotated, err := manager.issue(stored.Subject, stored.FamilyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return rotated, stored.Subject, nil
+}
+
+// Revoke will revoke refresh token family (e.g. on logout)
+func (manager *RefreshManager) Revoke(refreshToken string) error {
+	stored, err := manager.lookup(refreshToken)
+
+	switch {
+	case err == ErrRefreshTokenRevoked:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	return manager.Store.RevokeFamily(stored.FamilyID, manager.familyExpiresAt())
+}
+
+// issue is helper function to create and store new refresh token in family
+func (manager *RefreshManager) issue(subject, familyID string) (string, error) {
+	now := manager.now()
+
+	stored := &RefreshToken{
+		FamilyID:  familyID,
+		Subject:   subject,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(manager.ttl()),
+	}
+
+	var refreshToken string
+
+	if manager.Token != nil {
+		id, err := str.TokenWithEntropy(128, str.AlphabetBase62)
+		if err != nil {
+			return "", err
+		}
+
+		stored.ID = id
+
+		signer := *manager.Token
+
+		claims := &Claims{
+			Expiration: manager.ttl(),
+			Fields: map[string]interface{}{
+				refreshFamilyField: familyID,
+				refreshTypeField:   refreshType,
+			},
+		}
+		claims.Id = id
+		claims.Subject = subject
+
+		if err := signer.Generate(claims); err != nil {
+			return "", err
+		}
+
+		refreshToken = signer.Content
+	} else {
+		opaque, err := str.TokenWithEntropy(refreshTokenBits, str.AlphabetURLSafe)
+		if err != nil {
+			return "", err
+		}
+
+		// only hash of opaque token is stored, leaked store does not leak usable tokens
+		stored.ID = hashRefreshToken(opaque)
+		refreshToken = opaque
+	}
+
+	if err := manager.Store.Save(stored); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// lookup is helper function to find stored refresh token and check its expiration, family and subject revocation
+func (manager *RefreshManager) lookup(refreshToken string) (*RefreshToken, error) {
+	id, err := manager.tokenID(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := manager.Store.Get(id)
+	if err == ErrRefreshTokenNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !manager.now().Before(stored.ExpiresAt) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	revoked, err := manager.Store.FamilyRevoked(stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return stored, ErrRefreshTokenRevoked
+	}
+
+	if manager.Revocations != nil {
+		revokedAt, err := manager.Revocations.SubjectRevokedAt(stored.Subject)
+		if err != nil {
+			return nil, err
+		}
+
+		if !revokedAt.IsZero() && !stored.IssuedAt.After(revokedAt) {
+			return stored, ErrRefreshTokenRevoked
+		}
+	}
+
+	return stored, nil
+}
+
+// tokenID is helper function to get stored id of refresh token
+func (manager *RefreshManager) tokenID(refreshToken string) (string, error) {
+	if refreshToken == "" {
+		return "", ErrInvalidRefreshToken
+	}
+
+	if manager.Token == nil {
+		return hashRefreshToken(refreshToken), nil
+	}
+
+	// Validate rejects refresh tokens, only signature and registered claims are verified
+	claims, err := manager.Token.verify(refreshToken)
+	if err != nil || claims.Id == "" || claims.Fields[refreshTypeField] != refreshType {
+		return "", ErrInvalidRefreshToken
+	}
+
+	return claims.Id, nil
+}
+
+// familyExpiresAt is helper function to get time until which revoked family must be remembered
+func (manager *RefreshManager) familyExpiresAt() time.Time {
+	return manager.now().Add(manager.ttl())
+}
+
+// ttl is helper function to get TTL or default
+func (manager *RefreshManager) ttl() time.Duration {
+	if manager.TTL <= 0 {
+		return 30 * 24 * time.Hour
+	}
+
+	return manager.TTL
+}
+
+// now is helper function to get current time
+func (manager *RefreshManager) now() time.Time {
+	if manager.Now != nil {
+		return manager.Now()
+	}
+
+	return time.Now()
+}
+
+// hashRefreshToken is helper function to get hex SHA-256 of opaque refresh token
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+
+	return hex.EncodeToString(sum[:])
+}
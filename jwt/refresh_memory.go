@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryRefreshStore is in-memory RefreshStore, for tests and single instance services
+type MemoryRefreshStore struct {
+	mu       sync.Mutex
+	tokens   map[string]*RefreshToken
+	used     map[string]bool
+	families map[string]time.Time
+}
+
+// NewMemoryRefreshStore will initialize empty MemoryRefreshStore
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		tokens:   make(map[string]*RefreshToken),
+		used:     make(map[string]bool),
+		families: make(map[string]time.Time),
+	}
+}
+
+// Save implements RefreshStore.Save, expired tokens and revoked families are removed
+func (store *MemoryRefreshStore) Save(token *RefreshToken) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+
+	for id, t := range store.tokens {
+		if !now.Before(t.ExpiresAt) {
+			delete(store.tokens, id)
+			delete(store.used, id)
+		}
+	}
+
+	for familyID, expiresAt := range store.families {
+		if !now.Before(expiresAt) {
+			delete(store.families, familyID)
+		}
+	}
+
+	stored := *token
+	store.tokens[token.ID] = &stored
+
+	return nil
+}
+
+// Get implements RefreshStore.Get
+func (store *MemoryRefreshStore) Get(id string) (*RefreshToken, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	token, ok := store.tokens[id]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	stored := *token
+
+	return &stored, nil
+}
+
+// MarkUsed implements RefreshStore.MarkUsed
+func (store *MemoryRefreshStore) MarkUsed(token *RefreshToken) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.used[token.ID] {
+		return false, nil
+	}
+
+	store.used[token.ID] = true
+
+	return true, nil
+}
+
+// RevokeFamily implements RefreshStore.RevokeFamily
+func (store *MemoryRefreshStore) RevokeFamily(familyID string, expiresAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.families[familyID] = expiresAt
+
+	return nil
+}
+
+// FamilyRevoked implements RefreshStore.FamilyRevoked
+func (store *MemoryRefreshStore) FamilyRevoked(familyID string) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	_, revoked := store.families[familyID]
+
+	return revoked, nil
+}
@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"encoding/json"
+	"time"
+
+	redisLib "github.com/go-redis/redis"
+
+	"github.com/semirm-dev/godev/storage/redis"
+)
+
+// RedisRefreshStore is RefreshStore using Redis, keys expire together with tokens
+type RedisRefreshStore struct {
+	Connection *redis.Connection
+	// Prefix of keys, refresh: if not set
+	Prefix string
+}
+
+// NewRedisRefreshStore will initialize RedisRefreshStore with initialized connection
+func NewRedisRefreshStore(conn *redis.Connection) *RedisRefreshStore {
+	return &RedisRefreshStore{
+		Connection: conn,
+		Prefix:     "refresh:",
+	}
+}
+
+// Save implements RefreshStore.Save
+func (store *RedisRefreshStore) Save(token *RefreshToken) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return store.Connection.Store(&redis.Item{
+		Key:        store.key("token:", token.ID),
+		Value:      value,
+		Expiration: time.Until(token.ExpiresAt),
+	})
+}
+
+// Get implements RefreshStore.Get
+func (store *RedisRefreshStore) Get(id string) (*RefreshToken, error) {
+	value, err := store.Connection.Client.Get(store.key("token:", id)).Bytes()
+	if err == redisLib.Nil {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token := &RefreshToken{}
+	if err := json.Unmarshal(value, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// MarkUsed implements RefreshStore.MarkUsed using SETNX, so only one concurrent rotation succeeds
+func (store *RedisRefreshStore) MarkUsed(token *RefreshToken) (bool, error) {
+	return store.Connection.Client.SetNX(store.key("used:", token.ID), 1, time.Until(token.ExpiresAt)).Result()
+}
+
+// RevokeFamily implements RefreshStore.RevokeFamily
+func (store *RedisRefreshStore) RevokeFamily(familyID string, expiresAt time.Time) error {
+	return store.Connection.Store(&redis.Item{
+		Key:        store.key("family:", familyID),
+		Value:      1,
+		Expiration: time.Until(expiresAt),
+	})
+}
+
+// FamilyRevoked implements RefreshStore.FamilyRevoked
+func (store *RedisRefreshStore) FamilyRevoked(familyID string) (bool, error) {
+	n, err := store.Connection.Client.Exists(store.key("family:", familyID)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// key is helper function to build prefixed key
+func (store *RedisRefreshStore) key(kind, id string) string {
+	prefix := store.Prefix
+	if prefix == "" {
+		prefix = "refresh:"
+	}
+
+	return prefix + kind + id
+}
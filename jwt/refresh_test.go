@@ -0,0 +1,223 @@
+package jwt_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func refreshManagers() map[string]*jwt.RefreshManager {
+	opaque := jwt.NewRefreshManager(jwt.NewMemoryRefreshStore())
+
+	signed := jwt.NewRefreshManager(jwt.NewMemoryRefreshStore())
+	signed.Token = &jwt.Token{
+		Secret: secret,
+	}
+
+	return map[string]*jwt.RefreshManager{
+		"opaque": opaque,
+		"jwt":    signed,
+	}
+}
+
+func TestRefreshRotate(t *testing.T) {
+	for name, manager := range refreshManagers() {
+		refreshToken, err := manager.Issue("semir-123")
+
+		assert.NoError(t, err, name)
+		assert.NotEmpty(t, refreshToken)
+
+		rotated, subject, err := manager.Rotate(refreshToken)
+
+		assert.NoError(t, err, name)
+		assert.Equal(t, "semir-123", subject)
+		assert.NotEqual(t, refreshToken, rotated)
+
+		rotated, subject, err = manager.Rotate(rotated)
+
+		assert.NoError(t, err, name)
+		assert.Equal(t, "semir-123", subject)
+		assert.NotEmpty(t, rotated)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	for name, manager := range refreshManagers() {
+		stolen, err := manager.Issue("semir-123")
+		assert.NoError(t, err)
+
+		// legitimate user rotates first
+		current, _, err := manager.Rotate(stolen)
+		assert.NoError(t, err)
+
+		// attacker replays rotated token
+		_, _, err = manager.Rotate(stolen)
+		assert.Equal(t, jwt.ErrRefreshTokenReused, err, name)
+
+		// whole family is revoked
+		_, _, err = manager.Rotate(current)
+		assert.Equal(t, jwt.ErrRefreshTokenRevoked, err, name)
+
+		// other families are not affected
+		other, err := manager.Issue("semir-123")
+		assert.NoError(t, err)
+
+		_, _, err = manager.Rotate(other)
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestRefreshConcurrentRotate(t *testing.T) {
+	manager := jwt.NewRefreshManager(jwt.NewMemoryRefreshStore())
+
+	refreshToken, err := manager.Issue("semir-123")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, _, err := manager.Rotate(refreshToken); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+}
+
+func TestRefreshRevoke(t *testing.T) {
+	for name, manager := range refreshManagers() {
+		refreshToken, err := manager.Issue("semir-123")
+		assert.NoError(t, err)
+
+		rotated, _, err := manager.Rotate(refreshToken)
+		assert.NoError(t, err)
+
+		assert.NoError(t, manager.Revoke(rotated), name)
+		assert.NoError(t, manager.Revoke(rotated), name)
+
+		_, _, err = manager.Rotate(rotated)
+		assert.Equal(t, jwt.ErrRefreshTokenRevoked, err, name)
+	}
+}
+
+func TestRefreshRevokeSubject(t *testing.T) {
+	for name, manager := range refreshManagers() {
+		store := jwt.NewMemoryRevocationStore()
+		manager.Revocations = store
+
+		token := &jwt.Token{
+			Secret:      secret,
+			Revocations: store,
+		}
+
+		refreshToken, err := manager.Issue("semir-123")
+		assert.NoError(t, err)
+
+		other, err := manager.Issue("other-123")
+		assert.NoError(t, err)
+
+		assert.NoError(t, token.RevokeSubject("semir-123", time.Hour))
+
+		_, _, err = manager.Rotate(refreshToken)
+		assert.Equal(t, jwt.ErrRefreshTokenRevoked, err, name)
+
+		_, _, err = manager.Rotate(other)
+		assert.NoError(t, err, name)
+
+		// tokens issued after revocation are valid
+		manager.Now = func() time.Time {
+			return time.Now().Add(time.Millisecond)
+		}
+
+		refreshToken, err = manager.Issue("semir-123")
+		assert.NoError(t, err)
+
+		_, _, err = manager.Rotate(refreshToken)
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestRefreshExpired(t *testing.T) {
+	now := time.Now()
+
+	manager := jwt.NewRefreshManager(jwt.NewMemoryRefreshStore())
+	manager.TTL = time.Hour
+	manager.Now = func() time.Time {
+		return now
+	}
+
+	refreshToken, err := manager.Issue("semir-123")
+	assert.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+
+	_, _, err = manager.Rotate(refreshToken)
+	assert.Equal(t, jwt.ErrRefreshTokenExpired, err)
+}
+
+func TestRefreshInvalid(t *testing.T) {
+	for name, manager := range refreshManagers() {
+		_, _, err := manager.Rotate("")
+		assert.Equal(t, jwt.ErrInvalidRefreshToken, err, name)
+
+		_, _, err = manager.Rotate("unknown-token")
+		assert.Equal(t, jwt.ErrInvalidRefreshToken, err, name)
+	}
+
+	// access token signed with the same key is not refresh token
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	claims := &jwt.Claims{Expiration: time.Hour}
+	claims.Id = "access-token-id"
+
+	assert.NoError(t, token.Generate(claims))
+
+	_, _, err := refreshManagers()["jwt"].Rotate(token.Content)
+	assert.Equal(t, jwt.ErrInvalidRefreshToken, err)
+}
+
+func TestRefreshTokenNotAccessToken(t *testing.T) {
+	// refresh and access tokens share key, issuer and audience
+	access := &jwt.Token{
+		Secret:   secret,
+		Issuer:   "auth.example.com",
+		Audience: "api.example.com",
+	}
+
+	signer := *access
+
+	manager := jwt.NewRefreshManager(jwt.NewMemoryRefreshStore())
+	manager.Token = &signer
+
+	refreshToken, err := manager.Issue("semir-123")
+	assert.NoError(t, err)
+
+	_, err = access.Validate(refreshToken)
+	assert.True(t, errors.Is(err, jwt.ErrInvalidTokenType))
+
+	_, valid := access.ValidateAndExtract(refreshToken)
+	assert.False(t, valid)
+
+	// refresh token is still accepted by manager
+	_, subject, err := manager.Rotate(refreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "semir-123", subject)
+}
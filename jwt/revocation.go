@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/semirm-dev/godev/str"
+)
+
+var (
+	// ErrInvalidToken error
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrMissingRevocationStore error
+	ErrMissingRevocationStore = errors.New("missing revocation store")
+)
+
+// RevocationStore persists revoked tokens and subjects, implementations must be safe for concurrent use
+// Entries are needed only until revoked tokens would expire anyway
+type RevocationStore interface {
+	// Revoke revokes single token by its jti until expiresAt
+	Revoke(id string, expiresAt time.Time) error
+	// Revoked returns whether token with jti is revoked
+	Revoked(id string) (bool, error)
+	// RevokeSubject revokes all subject tokens issued at or before revokedAt, entry is kept until expiresAt
+	RevokeSubject(subject string, revokedAt, expiresAt time.Time) error
+	// SubjectRevokedAt returns time of last subject revocation, zero time if subject is not revoked
+	SubjectRevokedAt(subject string) (time.Time, error)
+}
+
+// Revoke will revoke given valid token (e.g. on logout), it is rejected by ValidateAndExtract until it expires
+// Validation error is returned if token is not valid, ErrInvalidToken if it has no jti
+func (token *Token) Revoke(tokenStr string) error {
+	if token.Revocations == nil {
+		return ErrMissingRevocationStore
+	}
+
+	claims, err := token.verify(tokenStr)
+	if err != nil {
+		return err
+	}
+
+	if claims.Id == "" {
+		return ErrInvalidToken
+	}
+
+	return token.Revocations.Revoke(claims.Id, time.Unix(claims.ExpiresAt, 0))
+}
+
+// RevokeSubject will revoke all tokens issued to subject so far (log out everywhere)
+// lifetime is the longest expiration of issued tokens, revocation is remembered that long
+// Issue time of tokens is taken from UUIDv7 jti set by Generate (milliseconds precision), so only tokens
+// issued within the same millisecond as revocation are revoked as well, for other jti iat (seconds precision) is used
+// Refresh tokens of RefreshManager sharing the same Revocations store are revoked as well
+func (token *Token) RevokeSubject(subject string, lifetime time.Duration) error {
+	if token.Revocations == nil {
+		return ErrMissingRevocationStore
+	}
+
+	now := token.now()
+
+	return token.Revocations.RevokeSubject(subject, now, now.Add(lifetime))
+}
+
+// checkRevoked is helper function to check claims against revocation store, returns ErrTokenRevoked or store error
+func (token *Token) checkRevoked(claims *Claims) error {
+	if token.Revocations == nil {
+		return nil
+	}
+
+	if claims.Id != "" {
+		revoked, err := token.Revocations.Revoked(claims.Id)
+		if err != nil {
+			return err
+		}
+
+		if revoked {
+			return &ValidationError{Err: ErrTokenRevoked}
+		}
+	}
+
+	if claims.Subject != "" {
+		revokedAt, err := token.Revocations.SubjectRevokedAt(claims.Subject)
+		if err != nil {
+			return err
+		}
+
+		if !revokedAt.IsZero() && !issuedAt(claims).After(revokedAt) {
+			return &ValidationError{Err: ErrTokenRevoked, Reason: "subject tokens revoked"}
+		}
+	}
+
+	return nil
+}
+
+// issuedAt is helper function to get issue time of claims, with milliseconds precision of UUIDv7 jti
+// if it matches iat, since iat has only seconds precision
+func issuedAt(claims *Claims) time.Time {
+	iat := time.Unix(claims.IssuedAt, 0)
+
+	id, err := str.ParseUUIDv7(claims.Id)
+	if err != nil {
+		return iat
+	}
+
+	if t := id.Time(); t.Unix() == claims.IssuedAt {
+		return t
+	}
+
+	return iat
+}
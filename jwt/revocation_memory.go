@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// revocation is revocation entry kept until expiresAt
+type revocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// MemoryRevocationStore is in-memory RevocationStore, for tests and single instance services
+type MemoryRevocationStore struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	subjects map[string]revocation
+}
+
+// NewMemoryRevocationStore will initialize empty MemoryRevocationStore
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens:   make(map[string]time.Time),
+		subjects: make(map[string]revocation),
+	}
+}
+
+// Revoke implements RevocationStore.Revoke, expired entries are removed
+func (store *MemoryRevocationStore) Revoke(id string, expiresAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.cleanup()
+	store.tokens[id] = expiresAt
+
+	return nil
+}
+
+// Revoked implements RevocationStore.Revoked
+func (store *MemoryRevocationStore) Revoked(id string) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	expiresAt, ok := store.tokens[id]
+
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// RevokeSubject implements RevocationStore.RevokeSubject, expired entries are removed
+func (store *MemoryRevocationStore) RevokeSubject(subject string, revokedAt, expiresAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.cleanup()
+	store.subjects[subject] = revocation{
+		revokedAt: revokedAt,
+		expiresAt: expiresAt,
+	}
+
+	return nil
+}
+
+// SubjectRevokedAt implements RevocationStore.SubjectRevokedAt
+func (store *MemoryRevocationStore) SubjectRevokedAt(subject string) (time.Time, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	r, ok := store.subjects[subject]
+	if !ok || !time.Now().Before(r.expiresAt) {
+		return time.Time{}, nil
+	}
+
+	return r.revokedAt, nil
+}
+
+// cleanup is helper function to remove expired entries, must be called with lock held
+func (store *MemoryRevocationStore) cleanup() {
+	now := time.Now()
+
+	for id, expiresAt := range store.tokens {
+		if !now.Before(expiresAt) {
+			delete(store.tokens, id)
+		}
+	}
+
+	for subject, r := range store.subjects {
+		if !now.Before(r.expiresAt) {
+			delete(store.subjects, subject)
+		}
+	}
+}
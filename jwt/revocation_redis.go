@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"strconv"
+	"time"
+
+	redisLib "github.com/go-redis/redis"
+
+	"github.com/semirm-dev/godev/storage/redis"
+)
+
+// RedisRevocationStore is RevocationStore using Redis, keys expire when revoked tokens would expire
+type RedisRevocationStore struct {
+	Connection *redis.Connection
+	// Prefix of keys, revoked: if not set
+	Prefix string
+}
+
+// NewRedisRevocationStore will initialize RedisRevocationStore with initialized connection
+func NewRedisRevocationStore(conn *redis.Connection) *RedisRevocationStore {
+	return &RedisRevocationStore{
+		Connection: conn,
+		Prefix:     "revoked:",
+	}
+}
+
+// Revoke implements RevocationStore.Revoke, key TTL is remaining token lifetime
+func (store *RedisRevocationStore) Revoke(id string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return store.Connection.Store(&redis.Item{
+		Key:        store.key("token:", id),
+		Value:      1,
+		Expiration: ttl,
+	})
+}
+
+// Revoked implements RevocationStore.Revoked
+func (store *RedisRevocationStore) Revoked(id string) (bool, error) {
+	n, err := store.Connection.Client.Exists(store.key("token:", id)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// RevokeSubject implements RevocationStore.RevokeSubject, revokedAt is stored as unix milliseconds
+func (store *RedisRevocationStore) RevokeSubject(subject string, revokedAt, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return store.Connection.Store(&redis.Item{
+		Key:        store.key("subject:", subject),
+		Value:      revokedAt.UnixNano() / int64(time.Millisecond),
+		Expiration: ttl,
+	})
+}
+
+// SubjectRevokedAt implements RevocationStore.SubjectRevokedAt
+func (store *RedisRevocationStore) SubjectRevokedAt(subject string) (time.Time, error) {
+	value, err := store.Connection.Client.Get(store.key("subject:", subject)).Result()
+	if err == redisLib.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+// key is helper function to build prefixed key
+func (store *RedisRevocationStore) key(kind, id string) string {
+	prefix := store.Prefix
+	if prefix == "" {
+		prefix = "revoked:"
+	}
+
+	return prefix + kind + id
+}
@@ -0,0 +1,157 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func generate(t *testing.T, token *jwt.Token, subject string) string {
+	claims := &jwt.Claims{
+		Expiration: time.Hour,
+		Fields:     fields,
+	}
+	claims.Subject = subject
+
+	assert.NoError(t, token.Generate(claims))
+
+	return token.Content
+}
+
+func TestGenerateSetsIDAndIssuedAt(t *testing.T) {
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	claims := &jwt.Claims{Expiration: time.Hour}
+	assert.NoError(t, token.Generate(claims))
+
+	assert.NotEmpty(t, claims.Id)
+	assert.InDelta(t, time.Now().Unix(), claims.IssuedAt, 1)
+
+	first := claims.Id
+
+	claims = &jwt.Claims{Expiration: time.Hour}
+	assert.NoError(t, token.Generate(claims))
+	assert.NotEqual(t, first, claims.Id)
+
+	// existing id is kept
+	claims = &jwt.Claims{Expiration: time.Hour}
+	claims.Id = "custom-id"
+	assert.NoError(t, token.Generate(claims))
+	assert.Equal(t, "custom-id", claims.Id)
+}
+
+func TestRevoke(t *testing.T) {
+	token := &jwt.Token{
+		Secret:      secret,
+		Revocations: jwt.NewMemoryRevocationStore(),
+	}
+
+	revoked := generate(t, token, "semir-123")
+	other := generate(t, token, "semir-123")
+
+	_, valid := token.ValidateAndExtract(revoked)
+	assert.True(t, valid)
+
+	assert.NoError(t, token.Revoke(revoked))
+
+	_, valid = token.ValidateAndExtract(revoked)
+	assert.False(t, valid)
+
+	_, valid = token.ValidateAndExtract(other)
+	assert.True(t, valid)
+
+	assert.True(t, errors.Is(token.Revoke("invalid"), jwt.ErrTokenMalformed))
+}
+
+func TestRevokeSubject(t *testing.T) {
+	store := jwt.NewMemoryRevocationStore()
+
+	token := &jwt.Token{
+		Secret:      secret,
+		Revocations: store,
+	}
+
+	first := generate(t, token, "semir-123")
+	second := generate(t, token, "semir-123")
+	other := generate(t, token, "other-123")
+
+	assert.NoError(t, token.RevokeSubject("semir-123", time.Hour))
+
+	_, valid := token.ValidateAndExtract(first)
+	assert.False(t, valid)
+
+	_, valid = token.ValidateAndExtract(second)
+	assert.False(t, valid)
+
+	_, valid = token.ValidateAndExtract(other)
+	assert.True(t, valid)
+
+	// tokens issued after revocation are valid
+	assert.NoError(t, store.RevokeSubject("semir-123", time.Now().Add(-2*time.Second), time.Now().Add(time.Hour)))
+
+	_, valid = token.ValidateAndExtract(generate(t, token, "semir-123"))
+	assert.True(t, valid)
+}
+
+func TestRevokeSubjectSameSecond(t *testing.T) {
+	store := jwt.NewMemoryRevocationStore()
+
+	token := &jwt.Token{
+		Secret:      secret,
+		Revocations: store,
+	}
+
+	revoked := generate(t, token, "semir-123")
+
+	assert.NoError(t, token.RevokeSubject("semir-123", time.Hour))
+	time.Sleep(2 * time.Millisecond)
+
+	// jti has milliseconds precision, token issued right after revocation is valid even within the same second
+	issued := generate(t, token, "semir-123")
+
+	_, valid := token.ValidateAndExtract(revoked)
+	assert.False(t, valid)
+
+	_, valid = token.ValidateAndExtract(issued)
+	assert.True(t, valid)
+
+	// without UUIDv7 jti iat is used, tokens issued within the same second as revocation are revoked
+	claims := &jwt.Claims{Expiration: time.Hour}
+	claims.Id = "custom-id"
+	claims.Subject = "semir-123"
+	assert.NoError(t, token.Generate(claims))
+
+	assert.NoError(t, store.RevokeSubject("semir-123", time.Unix(claims.IssuedAt, 0), time.Now().Add(time.Hour)))
+
+	_, valid = token.ValidateAndExtract(token.Content)
+	assert.False(t, valid)
+}
+
+func TestRevokeMissingStore(t *testing.T) {
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	assert.Equal(t, jwt.ErrMissingRevocationStore, token.Revoke(generate(t, token, "semir-123")))
+	assert.Equal(t, jwt.ErrMissingRevocationStore, token.RevokeSubject("semir-123", time.Hour))
+}
+
+func TestMemoryRevocationStoreExpiration(t *testing.T) {
+	store := jwt.NewMemoryRevocationStore()
+
+	assert.NoError(t, store.Revoke("expired", time.Now().Add(-time.Second)))
+	assert.NoError(t, store.RevokeSubject("expired", time.Now(), time.Now().Add(-time.Second)))
+
+	revoked, err := store.Revoked("expired")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revokedAt, err := store.SubjectRevokedAt("expired")
+	assert.NoError(t, err)
+	assert.True(t, revokedAt.IsZero())
+}
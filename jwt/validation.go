@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+)
+
+var (
+	// ErrTokenMalformed error
+	ErrTokenMalformed = errors.New("token is malformed")
+	// ErrTokenSignatureInvalid error
+	ErrTokenSignatureInvalid = errors.New("token signature is invalid")
+	// ErrTokenExpired error
+	ErrTokenExpired = errors.New("token is expired")
+	// ErrTokenNotValidYet error
+	ErrTokenNotValidYet = errors.New("token is not valid yet")
+	// ErrTokenUsedBeforeIssued error
+	ErrTokenUsedBeforeIssued = errors.New("token used before issued")
+	// ErrInvalidIssuer error
+	ErrInvalidIssuer = errors.New("token has invalid issuer")
+	// ErrInvalidAudience error
+	ErrInvalidAudience = errors.New("token has invalid audience")
+	// ErrTokenRevoked error
+	ErrTokenRevoked = errors.New("token is revoked")
+	// ErrInvalidTokenType error, returned for refresh token used as access token
+	ErrInvalidTokenType = errors.New("token has invalid type")
+)
+
+// ValidationError describes why token is not valid
+// Err is one of ErrToken* or ErrInvalid* errors, use errors.Is to check it
+type ValidationError struct {
+	Err    error
+	Reason string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	if e.Reason == "" {
+		return e.Err.Error()
+	}
+
+	return e.Err.Error() + ": " + e.Reason
+}
+
+// Unwrap returns Err
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Valid implements jwtLib.Claims, it checks exp, nbf and iat against current time without leeway
+// Token.Validate checks claims with its own clock, leeway, issuer and audience instead
+func (claims *Claims) Valid() error {
+	return claims.validate(time.Now(), 0, "", "")
+}
+
+// Decode will decode custom Fields into v, which should be pointer to struct with json tags
+func (claims *Claims) Decode(v interface{}) error {
+	data, err := json.Marshal(claims.Fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, aud is decoded from string or array into Audiences
+func (claims *Claims) UnmarshalJSON(data []byte) error {
+	// plain has no UnmarshalJSON, aud of StandardClaims is shadowed by aud below
+	type plain Claims
+
+	aux := &struct {
+		*plain
+		Audience audience `json:"aud,omitempty"`
+	}{
+		plain: (*plain)(claims),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	claims.Audiences = aux.Audience
+	claims.Audience = ""
+
+	if len(aux.Audience) == 1 {
+		claims.Audience = aux.Audience[0]
+	}
+
+	return nil
+}
+
+// validate is helper function to check registered claims, empty issuer and audience are not checked
+// exp is required, nbf and iat are checked only if set
+func (claims *Claims) validate(now time.Time, leeway time.Duration, issuer, audience string) error {
+	if claims.ExpiresAt == 0 {
+		return &ValidationError{Err: ErrTokenExpired, Reason: "exp claim is missing"}
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0).Add(leeway)) {
+		return &ValidationError{Err: ErrTokenExpired}
+	}
+
+	if claims.NotBefore != 0 && now.Add(leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return &ValidationError{Err: ErrTokenNotValidYet}
+	}
+
+	if claims.IssuedAt != 0 && now.Add(leeway).Before(time.Unix(claims.IssuedAt, 0)) {
+		return &ValidationError{Err: ErrTokenUsedBeforeIssued}
+	}
+
+	if issuer != "" && claims.Issuer != issuer {
+		return &ValidationError{Err: ErrInvalidIssuer, Reason: claims.Issuer}
+	}
+
+	if audience != "" && !claims.hasAudience(audience) {
+		reason := claims.Audience
+		if len(claims.Audiences) > 1 {
+			reason = strings.Join(claims.Audiences, ", ")
+		}
+
+		return &ValidationError{Err: ErrInvalidAudience, Reason: reason}
+	}
+
+	return nil
+}
+
+// hasAudience is helper function to check if audience is one of claims audiences
+func (claims *Claims) hasAudience(audience string) bool {
+	if len(claims.Audiences) == 0 {
+		return claims.Audience == audience
+	}
+
+	return contains(claims.Audiences, audience)
+}
+
+// audience is aud claim, decoded from string or array of strings
+type audience []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single != "" {
+			*a = audience{single}
+		}
+
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = multiple
+
+	return nil
+}
+
+// parseError is helper function to convert jwtLib parse error to ValidationError
+// Errors returned by verificationKey (e.g. ErrUnknownKey, ErrAlgorithmNotAllowed) are returned as they are
+func parseError(err error) error {
+	ve, ok := err.(*jwtLib.ValidationError)
+	if !ok {
+		return &ValidationError{Err: ErrTokenMalformed, Reason: err.Error()}
+	}
+
+	switch {
+	case ve.Errors&jwtLib.ValidationErrorMalformed != 0:
+		return &ValidationError{Err: ErrTokenMalformed, Reason: ve.Error()}
+	case ve.Errors&jwtLib.ValidationErrorUnverifiable != 0 && ve.Inner != nil:
+		return ve.Inner
+	case ve.Errors&jwtLib.ValidationErrorSignatureInvalid != 0:
+		return &ValidationError{Err: ErrTokenSignatureInvalid}
+	}
+
+	return &ValidationError{Err: ErrTokenMalformed, Reason: ve.Error()}
+}
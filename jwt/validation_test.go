@@ -0,0 +1,240 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	jwtLib "github.com/dgrijalva/jwt-go"
+	"github.com/semirm-dev/godev/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// sign is helper function to sign claims as they are, without Generate setting exp and iat
+func sign(token *jwt.Token, claims *jwt.Claims) error {
+	tokenStr, err := jwtLib.NewWithClaims(jwtLib.SigningMethodHS256, claims).SignedString(token.Secret)
+	token.Content = tokenStr
+
+	return err
+}
+
+func TestValidateClaims(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	clock := func() time.Time {
+		return now
+	}
+
+	issuer := &jwt.Token{
+		Secret:   secret,
+		Issuer:   "auth.example.com",
+		Audience: "api.example.com",
+		Now:      clock,
+	}
+
+	type suite struct {
+		Name      string
+		Claims    func(claims *jwt.Claims)
+		Verifier  jwt.Token
+		Expected  error
+		ExpectsOk bool
+	}
+
+	cases := []*suite{
+		{
+			Name:      "valid",
+			Claims:    func(claims *jwt.Claims) {},
+			Verifier:  jwt.Token{Issuer: "auth.example.com", Audience: "api.example.com"},
+			ExpectsOk: true,
+		},
+		{
+			Name:     "expired",
+			Claims:   func(claims *jwt.Claims) { claims.ExpiresAt = now.Add(-10 * time.Second).Unix() },
+			Expected: jwt.ErrTokenExpired,
+		},
+		{
+			Name:      "expired within leeway",
+			Claims:    func(claims *jwt.Claims) { claims.ExpiresAt = now.Add(-10 * time.Second).Unix() },
+			Verifier:  jwt.Token{Leeway: time.Minute},
+			ExpectsOk: true,
+		},
+		{
+			Name:     "missing exp",
+			Claims:   func(claims *jwt.Claims) { claims.ExpiresAt = 0 },
+			Expected: jwt.ErrTokenExpired,
+		},
+		{
+			Name:     "not valid yet",
+			Claims:   func(claims *jwt.Claims) { claims.NotBefore = now.Add(10 * time.Second).Unix() },
+			Expected: jwt.ErrTokenNotValidYet,
+		},
+		{
+			Name:      "not valid yet within leeway",
+			Claims:    func(claims *jwt.Claims) { claims.NotBefore = now.Add(10 * time.Second).Unix() },
+			Verifier:  jwt.Token{Leeway: time.Minute},
+			ExpectsOk: true,
+		},
+		{
+			Name:     "issued in future",
+			Claims:   func(claims *jwt.Claims) { claims.IssuedAt = now.Add(10 * time.Second).Unix() },
+			Expected: jwt.ErrTokenUsedBeforeIssued,
+		},
+		{
+			Name:     "wrong issuer",
+			Claims:   func(claims *jwt.Claims) {},
+			Verifier: jwt.Token{Issuer: "evil.example.com"},
+			Expected: jwt.ErrInvalidIssuer,
+		},
+		{
+			Name:     "wrong audience",
+			Claims:   func(claims *jwt.Claims) {},
+			Verifier: jwt.Token{Audience: "other.example.com"},
+			Expected: jwt.ErrInvalidAudience,
+		},
+	}
+
+	for _, c := range cases {
+		claims := &jwt.Claims{
+			Expiration: time.Hour,
+			Fields:     fields,
+		}
+
+		// claims are modified after generation, so token is re-signed with modified claims
+		assert.NoError(t, issuer.Generate(claims), c.Name)
+		c.Claims(claims)
+		assert.NoError(t, sign(issuer, claims), c.Name)
+
+		verifier := c.Verifier
+		verifier.Secret = secret
+		verifier.Now = clock
+
+		extracted, err := verifier.Validate(issuer.Content)
+
+		if c.ExpectsOk {
+			assert.NoError(t, err, c.Name)
+		} else {
+			assert.True(t, errors.Is(err, c.Expected), c.Name)
+
+			var validationErr *jwt.ValidationError
+			assert.True(t, errors.As(err, &validationErr), c.Name)
+		}
+
+		assert.Equal(t, fields, extracted.Fields, c.Name)
+
+		_, valid := verifier.ValidateAndExtract(issuer.Content)
+		assert.Equal(t, c.ExpectsOk, valid, c.Name)
+	}
+}
+
+func TestValidateAudienceArray(t *testing.T) {
+	signed := func(aud interface{}) string {
+		tokenStr, err := jwtLib.NewWithClaims(jwtLib.SigningMethodHS256, jwtLib.MapClaims{
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"aud": aud,
+		}).SignedString(secret)
+		assert.NoError(t, err)
+
+		return tokenStr
+	}
+
+	verifier := &jwt.Token{
+		Secret:   secret,
+		Audience: "api.example.com",
+	}
+
+	claims, err := verifier.Validate(signed([]string{"web.example.com", "api.example.com"}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"web.example.com", "api.example.com"}, claims.Audiences)
+	assert.Empty(t, claims.Audience)
+
+	claims, err = verifier.Validate(signed([]string{"api.example.com"}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "api.example.com", claims.Audience)
+
+	claims, err = verifier.Validate(signed("api.example.com"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api.example.com"}, claims.Audiences)
+	assert.Equal(t, "api.example.com", claims.Audience)
+
+	_, err = verifier.Validate(signed([]string{"web.example.com", "other.example.com"}))
+	assert.True(t, errors.Is(err, jwt.ErrInvalidAudience))
+
+	_, err = verifier.Validate(signed(123))
+	assert.True(t, errors.Is(err, jwt.ErrTokenMalformed))
+}
+
+func TestValidateSignatureAndFormat(t *testing.T) {
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	_, err := token.Validate("not-a-token")
+	assert.True(t, errors.Is(err, jwt.ErrTokenMalformed))
+
+	tokenStr := generate(t, token, "semir-123")
+
+	other := &jwt.Token{
+		Secret: []byte("other-secret"),
+	}
+
+	_, err = other.Validate(tokenStr)
+	assert.True(t, errors.Is(err, jwt.ErrTokenSignatureInvalid))
+
+	keys := testKeys(t)
+
+	verifier := &jwt.Token{
+		KeySet: jwt.NewStaticKeySet(keys[3].Public()),
+	}
+
+	_, err = verifier.Validate(tokenStr)
+	assert.Equal(t, jwt.ErrUnknownKey, err)
+}
+
+func TestValidateRevoked(t *testing.T) {
+	token := &jwt.Token{
+		Secret:      secret,
+		Revocations: jwt.NewMemoryRevocationStore(),
+	}
+
+	tokenStr := generate(t, token, "semir-123")
+
+	assert.NoError(t, token.Revoke(tokenStr))
+
+	_, err := token.Validate(tokenStr)
+	assert.True(t, errors.Is(err, jwt.ErrTokenRevoked))
+}
+
+func TestClaimsDecode(t *testing.T) {
+	token := &jwt.Token{
+		Secret: secret,
+	}
+
+	tokenStr := generate(t, token, "semir-123")
+
+	claims, err := token.Validate(tokenStr)
+	assert.NoError(t, err)
+
+	user := struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}{}
+
+	assert.NoError(t, claims.Decode(&user))
+	assert.Equal(t, "semir-123", user.ID)
+	assert.Equal(t, "semir", user.Username)
+	assert.Equal(t, "semir@mail.com", user.Email)
+}
+
+func TestClaimsValid(t *testing.T) {
+	claims := &jwt.Claims{}
+	claims.ExpiresAt = time.Now().Add(time.Hour).Unix()
+
+	assert.NoError(t, claims.Valid())
+
+	claims.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+
+	assert.True(t, errors.Is(claims.Valid(), jwt.ErrTokenExpired))
+}
@@ -0,0 +1,126 @@
+package str
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// monotonic generates millisecond timestamps and random entropy for sortable ids
+// Within the same millisecond entropy of previous id is incremented, so ids are strictly increasing
+// If clock goes backwards, last timestamp is kept, if entropy overflows, timestamp is advanced by 1 ms
+type monotonic struct {
+	mu      sync.Mutex
+	ms      uint64
+	entropy [10]byte
+	// bits of entropy used, the rest of entropy[0] high bits is zero
+	bits uint
+}
+
+// next will return timestamp (ms) and entropy of next id
+func (m *monotonic) next(now time.Time) (uint64, [10]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ms := uint64(now.UnixNano() / int64(time.Millisecond))
+
+	if ms <= m.ms && m.ms != 0 {
+		if m.increment() {
+			return m.ms, m.entropy, nil
+		}
+
+		ms = m.ms + 1
+	}
+
+	if _, err := io.ReadFull(rand.Reader, m.entropy[:]); err != nil {
+		return 0, m.entropy, err
+	}
+
+	m.entropy[0] &= byte(0xff >> (80 - m.bits))
+	m.ms = ms
+
+	return m.ms, m.entropy, nil
+}
+
+// increment is helper function to increment entropy by 1, returns false on overflow
+func (m *monotonic) increment() bool {
+	for i := len(m.entropy) - 1; i >= 0; i-- {
+		m.entropy[i]++
+		if m.entropy[i] != 0 {
+			break
+		}
+	}
+
+	return m.entropy[0]>>(m.bits-72) == 0 && m.entropy != [10]byte{}
+}
+
+// scanID is helper function implementing sql.Scanner.Scan for 16 bytes ids
+// Raw 16 bytes and text representation are accepted
+func scanID(id []byte, src interface{}, parse func(string) error) error {
+	switch v := src.(type) {
+	case nil:
+		copy(id, make([]byte, len(id)))
+		return nil
+	case string:
+		return parse(v)
+	case []byte:
+		if len(v) == len(id) {
+			copy(id, v)
+			return nil
+		}
+
+		return parse(string(v))
+	default:
+		return fmt.Errorf("can not scan %T into id", src)
+	}
+}
+
+// marshalCQL is helper function implementing gocql.Marshaler for 16 bytes ids
+// uuid and blob columns store raw bytes, text columns text representation
+func marshalCQL(info gocql.TypeInfo, id []byte, text string) ([]byte, error) {
+	switch info.Type() {
+	case gocql.TypeUUID, gocql.TypeBlob:
+		return id, nil
+	case gocql.TypeVarchar, gocql.TypeText, gocql.TypeAscii:
+		return []byte(text), nil
+	default:
+		return nil, fmt.Errorf("can not marshal id into %s", info)
+	}
+}
+
+// unmarshalCQL is helper function implementing gocql.Unmarshaler for 16 bytes ids
+func unmarshalCQL(info gocql.TypeInfo, data []byte, id []byte, parse func(string) error) error {
+	switch info.Type() {
+	case gocql.TypeUUID, gocql.TypeBlob, gocql.TypeVarchar, gocql.TypeText, gocql.TypeAscii:
+		if len(data) == 0 {
+			copy(id, make([]byte, len(id)))
+			return nil
+		}
+
+		return scanID(id, data, parse)
+	default:
+		return fmt.Errorf("can not unmarshal %s into id", info)
+	}
+}
+
+// putMillis is helper function to put 48 bits millisecond timestamp to first 6 bytes of id
+func putMillis(id []byte, ms uint64) {
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// millisTime is helper function to get time of 48 bits millisecond timestamp in first 6 bytes of id
+func millisTime(id []byte) time.Time {
+	var ms int64
+	for i := 0; i < 6; i++ {
+		ms = ms<<8 | int64(id[i])
+	}
+
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
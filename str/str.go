@@ -22,6 +22,8 @@ func UUID() string {
 }
 
 // Random - generate random string using masking with source
+// It is NOT cryptographically-secure, output is predictable and may repeat,
+// use Token for passwords, reset tokens, API keys and similar
 //
 // Credits to: https://medium.com/@kpbird/golang-generate-fixed-size-random-string-dd6dbd5e63c0
 func Random(n int) string {
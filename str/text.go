@@ -0,0 +1,274 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations of letters which do not decompose to ASCII letter and combining marks
+var transliterations = map[rune]string{
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'ł': "l", 'Ł': "L",
+	'ø': "o", 'Ø': "O",
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'þ': "th", 'Þ': "TH",
+	'ı': "i",
+	'ħ': "h", 'Ħ': "H",
+}
+
+// NormalizeNFC will return canonical composition (NFC) of s, e.g. "é" to "é"
+// Strings should be normalized before comparing or storing them
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeNFKC will return compatibility composition (NFKC) of s, e.g. "ﬁ" to "fi", "①" to "1"
+// Useful for identifiers such as usernames, where visually similar strings must be equal
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// Transliterate will replace accented and special latin letters with their ASCII equivalents, e.g. "Đurđevak" to "Durdevak"
+// Letters without ASCII equivalent are kept
+func Transliterate(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range norm.NFKC.String(s) {
+		if t, ok := transliterations[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	removeMarks := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	result, _, err := transform.String(removeMarks, b.String())
+	if err != nil {
+		return b.String()
+	}
+
+	return result
+}
+
+// Slugify will return URL friendly slug of s: transliterated, lowercase, words separated with -
+// e.g. "Šta ćeš, Đorđe?" to "sta-ces-dorde"
+func Slugify(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	dash := false
+
+	for _, r := range strings.ToLower(Transliterate(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(r)
+			dash = false
+
+			continue
+		}
+
+		dash = true
+	}
+
+	return b.String()
+}
+
+// SnakeCase will convert s to snake_case, e.g. "HTTPServer ID" to "http_server_id"
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// KebabCase will convert s to kebab-case, e.g. "HTTPServer ID" to "http-server-id"
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+// CamelCase will convert s to camelCase, e.g. "http_server id" to "httpServerId"
+func CamelCase(s string) string {
+	var b strings.Builder
+
+	for i, word := range words(s) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+			continue
+		}
+
+		b.WriteString(title(word))
+	}
+
+	return b.String()
+}
+
+// PascalCase will convert s to PascalCase, e.g. "http_server id" to "HttpServerId"
+func PascalCase(s string) string {
+	var b strings.Builder
+
+	for _, word := range words(s) {
+		b.WriteString(title(word))
+	}
+
+	return b.String()
+}
+
+// CollapseWhitespace will replace each sequence of Unicode white space with single space and trim s
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Truncate will shorten s to at most n runes, including ellipsis, e.g. "…" or "..."
+// s is returned as is if it is not longer than n
+func Truncate(s string, n int, ellipsis string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return cut(s, n, ellipsis, utf8.RuneCountInString(ellipsis), func(s string) int {
+		_, size := utf8.DecodeRuneInString(s)
+		return size
+	})
+}
+
+// TruncateGraphemes will shorten s to at most n user-perceived characters (grapheme clusters), including ellipsis
+// Accented letters, emoji with modifiers and ZWJ sequences and flags are never split
+// Grapheme clusters are approximated: base rune with following combining marks, variation selectors,
+// emoji modifiers and ZWJ joined runes, or pair of regional indicators
+func TruncateGraphemes(s string, n int, ellipsis string) string {
+	if graphemeCount(s) <= n {
+		return s
+	}
+
+	return cut(s, n, ellipsis, graphemeCount(ellipsis), graphemeSize)
+}
+
+// cut is helper function to keep first n - ellipsisLen units (runes, graphemes) of s and append ellipsis
+func cut(s string, n int, ellipsis string, ellipsisLen int, unitSize func(string) int) string {
+	keep := n - ellipsisLen
+	if keep < 0 {
+		keep = n
+		ellipsis = ""
+	}
+
+	end := 0
+	for i := 0; i < keep && end < len(s); i++ {
+		end += unitSize(s[end:])
+	}
+
+	return s[:end] + ellipsis
+}
+
+// graphemeCount is helper function to count approximated grapheme clusters of s
+func graphemeCount(s string) int {
+	count := 0
+
+	for len(s) > 0 {
+		s = s[graphemeSize(s):]
+		count++
+	}
+
+	return count
+}
+
+// graphemeSize is helper function to get size in bytes of first approximated grapheme cluster of s
+func graphemeSize(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+
+	if isRegionalIndicator(r) {
+		if next, nextSize := utf8.DecodeRuneInString(s[size:]); isRegionalIndicator(next) {
+			return size + nextSize
+		}
+
+		return size
+	}
+
+	for size < len(s) {
+		next, nextSize := utf8.DecodeRuneInString(s[size:])
+
+		switch {
+		case next == '\u200d':
+			// zero width joiner joins next rune as well
+			size += nextSize
+
+			if size < len(s) {
+				_, joinedSize := utf8.DecodeRuneInString(s[size:])
+				size += joinedSize
+			}
+		case unicode.In(next, unicode.Mn, unicode.Me, unicode.Mc),
+			next >= '\ufe00' && next <= '\ufe0f',
+			next >= 0x1f3fb && next <= 0x1f3ff:
+			// combining marks, variation selectors, emoji skin tone modifiers
+			size += nextSize
+		default:
+			return size
+		}
+	}
+
+	return size
+}
+
+// isRegionalIndicator is helper function to check if r is regional indicator symbol, flags consist of two
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// words is helper function to split s into words on non letters and digits and on case changes
+// Acronyms are kept together: "HTTPServer" is split to "HTTP" and "Server"
+func words(s string) []string {
+	var result []string
+
+	runes := []rune(s)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				result = append(result, string(runes[start:i]))
+				start = -1
+			}
+
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+
+		// fooBar, or HTTPServer at S
+		lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		acronymEnd := unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		if lowerToUpper || acronymEnd {
+			result = append(result, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		result = append(result, string(runes[start:]))
+	}
+
+	return result
+}
+
+// title is helper function to uppercase first letter of word and lowercase the rest
+func title(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+
+	return string(unicode.ToUpper(r)) + strings.ToLower(word[size:])
+}
@@ -0,0 +1,158 @@
+package str_test
+
+import (
+	"testing"
+
+	"github.com/semirm-dev/godev/str"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		nfc   string
+		nfkc  string
+	}{
+		{"e\u0301", "é", "é"},
+		{"é", "é", "é"},
+		{"ﬁle", "ﬁle", "file"},
+		{"① Ａ", "① Ａ", "1 A"},
+		{"plain", "plain", "plain"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.nfc, str.NormalizeNFC(tt.input), tt.input)
+		assert.Equal(t, tt.nfkc, str.NormalizeNFKC(tt.input), tt.input)
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := map[string]string{
+		"Đurđevak":     "Durdevak",
+		"čćžš ČĆŽŠ":    "cczs CCZS",
+		"Straße":       "Strasse",
+		"Æble og Øl":   "AEble og Ol",
+		"Łódź":         "Lodz",
+		"Þór":          "THor",
+		"crème brûlée": "creme brulee",
+		"e\u0301":      "e",
+		"Москва":       "Москва",
+		"ﬁnancial":     "financial",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, str.Transliterate(input), input)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":            "hello-world",
+		"  Šta ćeš, Đorđe?  ":    "sta-ces-dorde",
+		"Große Straße 12":        "grosse-strasse-12",
+		"already-a-slug":         "already-a-slug",
+		"multiple   ---  dashes": "multiple-dashes",
+		"C'est déjà l'été!":      "c-est-deja-l-ete",
+		"HTTPServer v2.0":        "httpserver-v2-0",
+		"!!!":                    "",
+		"":                       "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, str.Slugify(input), input)
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		input  string
+		snake  string
+		kebab  string
+		camel  string
+		pascal string
+	}{
+		{"hello world", "hello_world", "hello-world", "helloWorld", "HelloWorld"},
+		{"HelloWorld", "hello_world", "hello-world", "helloWorld", "HelloWorld"},
+		{"helloWorld", "hello_world", "hello-world", "helloWorld", "HelloWorld"},
+		{"HTTPServer ID", "http_server_id", "http-server-id", "httpServerId", "HttpServerId"},
+		{"user_id", "user_id", "user-id", "userId", "UserId"},
+		{"user-ID", "user_id", "user-id", "userId", "UserId"},
+		{"  leading and trailing  ", "leading_and_trailing", "leading-and-trailing", "leadingAndTrailing", "LeadingAndTrailing"},
+		{"version2Update", "version2_update", "version2-update", "version2Update", "Version2Update"},
+		{"čaša vode", "čaša_vode", "čaša-vode", "čašaVode", "ČašaVode"},
+		{"", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.snake, str.SnakeCase(tt.input), tt.input)
+		assert.Equal(t, tt.kebab, str.KebabCase(tt.input), tt.input)
+		assert.Equal(t, tt.camel, str.CamelCase(tt.input), tt.input)
+		assert.Equal(t, tt.pascal, str.PascalCase(tt.input), tt.input)
+	}
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	tests := map[string]string{
+		"  hello   world  ":         "hello world",
+		"tabs\t\tand\nnew\r\nlines": "tabs and new lines",
+		"no\u00a0break\u2003space":  "no break space",
+		"single":                    "single",
+		"   ":                       "",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, str.CollapseWhitespace(input), input)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		ellipsis string
+		expected string
+	}{
+		{"hello world", 20, "…", "hello world"},
+		{"hello world", 11, "…", "hello world"},
+		{"hello world", 8, "…", "hello w…"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello world", 5, "", "hello"},
+		{"čćžšđ", 3, "…", "čć…"},
+		{"hello", 2, "...", "he"},
+		{"hello", 0, "…", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, str.Truncate(tt.input, tt.n, tt.ellipsis), "%q %d", tt.input, tt.n)
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		ellipsis string
+		expected string
+	}{
+		// decomposed accents stay with their letter
+		{"e\u0301e\u0301e\u0301e\u0301", 3, "…", "e\u0301e\u0301…"},
+		// Truncate would split accent from letter
+		{"cafe\u0301s", 4, "", "cafe\u0301"},
+		// emoji with skin tone modifier
+		{"\U0001f44d\U0001f3fd\U0001f44d\U0001f3fd\U0001f44d\U0001f3fd", 2, "", "\U0001f44d\U0001f3fd\U0001f44d\U0001f3fd"},
+		// ZWJ family sequence is single grapheme
+		{"\U0001f468\u200d\U0001f469\u200d\U0001f467 family", 3, "…", "\U0001f468\u200d\U0001f469\u200d\U0001f467 …"},
+		// flags are pairs of regional indicators
+		{"\U0001f1e7\U0001f1e6\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7", 2, "", "\U0001f1e7\U0001f1e6\U0001f1e9\U0001f1ea"},
+		// variation selector
+		{"❤\ufe0f❤\ufe0f", 1, "", "❤\ufe0f"},
+		{"short", 10, "…", "short"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, str.TruncateGraphemes(tt.input, tt.n, tt.ellipsis), "%q %d", tt.input, tt.n)
+	}
+
+	assert.Equal(t, "cafe", str.Truncate("cafe\u0301s", 4, ""))
+}
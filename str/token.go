@@ -0,0 +1,101 @@
+package str
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math"
+)
+
+// Token alphabets
+const (
+	// AlphabetBase62 is digits, uppercase and lowercase letters
+	AlphabetBase62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// AlphabetBase32 is RFC 4648 base32 alphabet
+	AlphabetBase32 = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	// AlphabetURLSafe is base64 URL alphabet, safe in URLs and file names without escaping
+	AlphabetURLSafe = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// AlphabetHex is lowercase hex alphabet
+	AlphabetHex = "0123456789abcdef"
+)
+
+// ErrInvalidAlphabet error
+var ErrInvalidAlphabet = errors.New("alphabet must have 2 to 128 unique ASCII characters")
+
+// ErrInvalidEntropy error
+var ErrInvalidEntropy = errors.New("token entropy must be positive")
+
+// Token will return cryptographically-secure random string of n characters from alphabet
+// Characters are sampled uniformly (rejection sampling), so there is no modulo bias
+func Token(n int, alphabet string) (string, error) {
+	if err := validateAlphabet(alphabet); err != nil {
+		return "", err
+	}
+
+	if n <= 0 {
+		return "", nil
+	}
+
+	// smallest all 1-bits mask covering alphabet indices
+	mask := 1
+	for mask < len(alphabet)-1 {
+		mask = mask<<1 | 1
+	}
+
+	token := make([]byte, 0, n)
+	buf := make([]byte, n+n/2)
+
+	for len(token) < n {
+		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			if idx := int(b) & mask; idx < len(alphabet) {
+				token = append(token, alphabet[idx])
+
+				if len(token) == n {
+					break
+				}
+			}
+		}
+	}
+
+	return string(token), nil
+}
+
+// TokenWithEntropy will return cryptographically-secure random string from alphabet,
+// long enough to have at least bits of entropy, e.g. 128 bits is 22 base62 characters
+func TokenWithEntropy(bits int, alphabet string) (string, error) {
+	if err := validateAlphabet(alphabet); err != nil {
+		return "", err
+	}
+
+	if bits <= 0 {
+		return "", ErrInvalidEntropy
+	}
+
+	n := int(math.Ceil(float64(bits) / math.Log2(float64(len(alphabet)))))
+
+	return Token(n, alphabet)
+}
+
+// validateAlphabet is helper function to validate token alphabet
+func validateAlphabet(alphabet string) error {
+	if len(alphabet) < 2 || len(alphabet) > 128 {
+		return ErrInvalidAlphabet
+	}
+
+	var seen [128]bool
+
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 128 || seen[c] {
+			return ErrInvalidAlphabet
+		}
+
+		seen[c] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package str_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/semirm-dev/godev/str"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	alphabets := []string{str.AlphabetBase62, str.AlphabetBase32, str.AlphabetURLSafe, str.AlphabetHex, "01"}
+
+	for _, alphabet := range alphabets {
+		for _, n := range []int{1, 16, 32, 100} {
+			token, err := str.Token(n, alphabet)
+
+			assert.NoError(t, err)
+			assert.Len(t, token, n)
+
+			for _, c := range token {
+				assert.True(t, strings.ContainsRune(alphabet, c), "%q not in %q", c, alphabet)
+			}
+		}
+	}
+
+	token, err := str.Token(0, str.AlphabetHex)
+
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+}
+
+func TestTokenUnique(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for i := 0; i < 1000; i++ {
+		token, err := str.Token(16, str.AlphabetBase62)
+		assert.NoError(t, err)
+
+		assert.False(t, seen[token])
+		seen[token] = true
+	}
+}
+
+func TestTokenUnbiased(t *testing.T) {
+	// 62 is not power of 2, modulo sampling would favour first 8 characters
+	token, err := str.Token(62*2000, str.AlphabetBase62)
+	assert.NoError(t, err)
+
+	counts := make(map[rune]int)
+	for _, c := range token {
+		counts[c]++
+	}
+
+	assert.Len(t, counts, 62)
+
+	for c, count := range counts {
+		assert.InDelta(t, 2000, count, 300, "character %q", c)
+	}
+}
+
+func TestTokenWithEntropy(t *testing.T) {
+	cases := map[string]int{
+		str.AlphabetBase62:  22,
+		str.AlphabetBase32:  26,
+		str.AlphabetURLSafe: 22,
+		str.AlphabetHex:     32,
+	}
+
+	for alphabet, expected := range cases {
+		token, err := str.TokenWithEntropy(128, alphabet)
+
+		assert.NoError(t, err)
+		assert.Len(t, token, expected)
+	}
+}
+
+func TestTokenWithEntropyInvalid(t *testing.T) {
+	for _, bits := range []int{0, -1} {
+		token, err := str.TokenWithEntropy(bits, str.AlphabetBase62)
+
+		assert.Equal(t, str.ErrInvalidEntropy, err)
+		assert.Empty(t, token)
+	}
+}
+
+func TestTokenInvalidAlphabet(t *testing.T) {
+	alphabets := []string{"", "a", "aab", "abcč", strings.Repeat("a", 300)}
+
+	for _, alphabet := range alphabets {
+		_, err := str.Token(10, alphabet)
+		assert.Equal(t, str.ErrInvalidAlphabet, err)
+
+		_, err = str.TokenWithEntropy(128, alphabet)
+		assert.Equal(t, str.ErrInvalidAlphabet, err)
+	}
+}
@@ -0,0 +1,195 @@
+package str
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// crockford is Crockford's base32 alphabet used by ULID
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLen is length of ULID text representation
+const ulidLen = 26
+
+// ErrInvalidULID error
+var ErrInvalidULID = errors.New("invalid ulid")
+
+// ULID is lexicographically sortable unique id: 48 bits millisecond timestamp | 80 bits randomness
+// Text representation is 26 characters of Crockford's base32, e.g. 01ARZ3NDEKTSV4RRFFQ69G5FAV
+type ULID [16]byte
+
+// ulidGenerator generates monotonic ULIDs
+var ulidGenerator = &monotonic{bits: 80}
+
+// ulidDecoding maps Crockford's base32 characters to their values, 0xff for invalid ones
+var ulidDecoding = func() [256]byte {
+	var dec [256]byte
+	for i := range dec {
+		dec[i] = 0xff
+	}
+
+	for i := 0; i < len(crockford); i++ {
+		dec[crockford[i]] = byte(i)
+		dec[crockford[i]|0x20] = byte(i) // lowercase
+	}
+
+	return dec
+}()
+
+// NewULID will return new ULID, ULIDs generated within the same millisecond are strictly increasing
+func NewULID() (ULID, error) {
+	var id ULID
+
+	ms, entropy, err := ulidGenerator.next(time.Now())
+	if err != nil {
+		return id, err
+	}
+
+	putMillis(id[:], ms)
+	copy(id[6:], entropy[:])
+
+	return id, nil
+}
+
+// ParseULID will parse ULID text representation, case insensitive
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+
+	if len(s) != ulidLen {
+		return id, ErrInvalidULID
+	}
+
+	// first character holds only 3 bits
+	if ulidDecoding[s[0]] > 7 {
+		return id, ErrInvalidULID
+	}
+
+	// 26 characters * 5 bits = 130 bits, the 2 leading bits are zero
+	var acc uint64
+	var bits uint
+	n := 0
+
+	for i := 0; i < ulidLen; i++ {
+		v := ulidDecoding[s[i]]
+		if v == 0xff {
+			return ULID{}, ErrInvalidULID
+		}
+
+		acc = acc<<5 | uint64(v)
+		bits += 5
+
+		if i == 0 {
+			// drop 2 leading zero bits
+			bits -= 2
+		}
+
+		for bits >= 8 {
+			bits -= 8
+			id[n] = byte(acc >> bits)
+			n++
+		}
+	}
+
+	return id, nil
+}
+
+// String will return ULID text representation
+func (id ULID) String() string {
+	text := make([]byte, ulidLen)
+
+	var acc uint64
+	var bits uint
+	n := ulidLen - 1
+
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint64(id[i]) << bits
+		bits += 8
+
+		for bits >= 5 {
+			text[n] = crockford[acc&0x1f]
+			acc >>= 5
+			bits -= 5
+			n--
+		}
+	}
+
+	text[0] = crockford[acc&0x1f]
+
+	return string(text)
+}
+
+// Time will return ULID timestamp, with millisecond precision
+func (id ULID) Time() time.Time {
+	return millisTime(id[:])
+}
+
+// Bytes will return ULID as 16 bytes
+func (id ULID) Bytes() []byte {
+	return id[:]
+}
+
+// IsZero returns whether ULID is zero value
+func (id ULID) IsZero() bool {
+	return id == ULID{}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (id ULID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (id ULID) MarshalBinary() ([]byte, error) {
+	return id.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (id *ULID) UnmarshalBinary(data []byte) error {
+	if len(data) != len(id) {
+		return ErrInvalidULID
+	}
+
+	copy(id[:], data)
+
+	return nil
+}
+
+// Scan implements sql.Scanner, accepts text representation or 16 bytes
+func (id *ULID) Scan(src interface{}) error {
+	return scanID(id[:], src, id.parse)
+}
+
+// Value implements driver.Valuer, ULID is stored as text
+func (id ULID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// MarshalCQL implements gocql.Marshaler, ULID is stored as 16 bytes in uuid and blob columns or as text
+func (id ULID) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	return marshalCQL(info, id[:], id.String())
+}
+
+// UnmarshalCQL implements gocql.Unmarshaler
+func (id *ULID) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	return unmarshalCQL(info, data, id[:], id.parse)
+}
+
+// parse is helper function to parse s into id
+func (id *ULID) parse(s string) error {
+	return id.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,148 @@
+package str_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/semirm-dev/godev/str"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+
+	id, err := str.NewULID()
+	assert.NoError(t, err)
+
+	assert.Len(t, id.String(), 26)
+	assert.False(t, id.IsZero())
+	assert.False(t, id.Time().Before(before))
+	assert.False(t, id.Time().After(time.Now()))
+
+	parsed, err := str.ParseULID(id.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	parsed, err = str.ParseULID(strings.ToLower(id.String()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+}
+
+func TestULIDKnown(t *testing.T) {
+	id, err := str.ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1469922850259), id.Time().UnixNano()/int64(time.Millisecond))
+	assert.Equal(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", id.String())
+
+	max, err := str.ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, str.ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, max)
+}
+
+func TestULIDInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"01ARZ3NDEKTSV4RRFFQ69G5FA",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAVV",
+		"80000000000000000000000000",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU",
+		"01ARZ3NDEKTSV4RRFFQ69G5FA!",
+	}
+
+	for _, s := range invalid {
+		_, err := str.ParseULID(s)
+		assert.Equal(t, str.ErrInvalidULID, err, s)
+	}
+}
+
+func TestULIDMonotonic(t *testing.T) {
+	prev, err := str.NewULID()
+	assert.NoError(t, err)
+
+	for i := 0; i < 10000; i++ {
+		id, err := str.NewULID()
+		assert.NoError(t, err)
+
+		if !assert.True(t, id.String() > prev.String(), "%s <= %s", id, prev) {
+			return
+		}
+
+		prev = id
+	}
+}
+
+func TestULIDMarshaling(t *testing.T) {
+	id, err := str.NewULID()
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(map[string]str.ULID{"id": id})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"`+id.String()+`"}`, string(data))
+
+	var decoded map[string]str.ULID
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, id, decoded["id"])
+
+	binary, err := id.MarshalBinary()
+	assert.NoError(t, err)
+
+	var fromBinary str.ULID
+
+	assert.NoError(t, fromBinary.UnmarshalBinary(binary))
+	assert.Equal(t, id, fromBinary)
+	assert.Equal(t, str.ErrInvalidULID, fromBinary.UnmarshalBinary(binary[1:]))
+}
+
+func TestULIDSQL(t *testing.T) {
+	id, err := str.NewULID()
+	assert.NoError(t, err)
+
+	value, err := id.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, id.String(), value)
+
+	for _, src := range []interface{}{id.String(), []byte(id.String()), id.Bytes()} {
+		var scanned str.ULID
+
+		assert.NoError(t, scanned.Scan(src))
+		assert.Equal(t, id, scanned)
+	}
+
+	var scanned str.ULID
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.True(t, scanned.IsZero())
+	assert.Error(t, scanned.Scan(42))
+}
+
+func TestULIDCQL(t *testing.T) {
+	id, err := str.NewULID()
+	assert.NoError(t, err)
+
+	for _, typ := range []gocql.Type{gocql.TypeUUID, gocql.TypeBlob, gocql.TypeText, gocql.TypeVarchar} {
+		info := gocql.NewNativeType(4, typ, "")
+
+		data, err := gocql.Marshal(info, id)
+		assert.NoError(t, err)
+
+		var unmarshaled str.ULID
+
+		assert.NoError(t, gocql.Unmarshal(info, data, &unmarshaled))
+		assert.Equal(t, id, unmarshaled)
+	}
+
+	_, err = gocql.Marshal(gocql.NewNativeType(4, gocql.TypeInt, ""), id)
+	assert.Error(t, err)
+}
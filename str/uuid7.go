@@ -0,0 +1,173 @@
+package str
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// uuidLen is length of UUID text representation
+const uuidLen = 36
+
+// ErrInvalidUUID error
+var ErrInvalidUUID = errors.New("invalid uuid")
+
+// UUIDv7 is time-ordered UUID (RFC 9562): 48 bits millisecond timestamp | version | 74 bits randomness
+// Unlike random v4 UUID it keeps B-tree indexes compact, since new ids are appended at the end
+type UUIDv7 [16]byte
+
+// uuidv7Generator generates monotonic UUIDv7s
+var uuidv7Generator = &monotonic{bits: 74}
+
+// NewUUIDv7 will return new UUIDv7, UUIDs generated within the same millisecond are strictly increasing
+func NewUUIDv7() (UUIDv7, error) {
+	var id UUIDv7
+
+	ms, entropy, err := uuidv7Generator.next(time.Now())
+	if err != nil {
+		return id, err
+	}
+
+	putMillis(id[:], ms)
+
+	// entropy holds 74 bits: 12 bits rand_a | 62 bits rand_b
+	id[6] = 0x70 | entropy[0]<<2 | entropy[1]>>6
+	id[7] = entropy[1]<<2 | entropy[2]>>6
+	id[8] = 0x80 | entropy[2]&0x3f
+	copy(id[9:], entropy[3:])
+
+	return id, nil
+}
+
+// ParseUUIDv7 will parse UUID text representation, UUIDs of other versions are rejected
+func ParseUUIDv7(s string) (UUIDv7, error) {
+	var id UUIDv7
+
+	if len(s) != uuidLen || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, ErrInvalidUUID
+	}
+
+	src := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(id[:], []byte(src)); err != nil {
+		return UUIDv7{}, ErrInvalidUUID
+	}
+
+	if !id.valid() {
+		return UUIDv7{}, ErrInvalidUUID
+	}
+
+	return id, nil
+}
+
+// String will return UUID text representation, e.g. 017f22e2-79b0-7cc3-98c4-dc0c0c07398f
+func (id UUIDv7) String() string {
+	text := make([]byte, uuidLen)
+
+	hex.Encode(text[0:8], id[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], id[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], id[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], id[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:], id[10:])
+
+	return string(text)
+}
+
+// Time will return UUID timestamp, with millisecond precision
+func (id UUIDv7) Time() time.Time {
+	return millisTime(id[:])
+}
+
+// Bytes will return UUID as 16 bytes
+func (id UUIDv7) Bytes() []byte {
+	return id[:]
+}
+
+// IsZero returns whether UUID is zero value
+func (id UUIDv7) IsZero() bool {
+	return id == UUIDv7{}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (id UUIDv7) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *UUIDv7) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUIDv7(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (id UUIDv7) MarshalBinary() ([]byte, error) {
+	return id.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (id *UUIDv7) UnmarshalBinary(data []byte) error {
+	if len(data) != len(id) {
+		return ErrInvalidUUID
+	}
+
+	var parsed UUIDv7
+	copy(parsed[:], data)
+
+	if !parsed.valid() {
+		return ErrInvalidUUID
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// Scan implements sql.Scanner, accepts text representation or 16 bytes
+func (id *UUIDv7) Scan(src interface{}) error {
+	if data, ok := src.([]byte); ok && len(data) == len(id) {
+		return id.UnmarshalBinary(data)
+	}
+
+	return scanID(id[:], src, id.parse)
+}
+
+// Value implements driver.Valuer, UUID is stored as text, e.g. into Postgres uuid column
+func (id UUIDv7) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// MarshalCQL implements gocql.Marshaler, UUID is stored as 16 bytes in uuid and blob columns or as text
+func (id UUIDv7) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	return marshalCQL(info, id[:], id.String())
+}
+
+// UnmarshalCQL implements gocql.Unmarshaler
+func (id *UUIDv7) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	if len(data) == len(id) {
+		return id.UnmarshalBinary(data)
+	}
+
+	return unmarshalCQL(info, data, id[:], id.parse)
+}
+
+// parse is helper function to parse s into id
+func (id *UUIDv7) parse(s string) error {
+	return id.UnmarshalText([]byte(s))
+}
+
+// valid is helper function to check version (7) and variant (RFC 9562) bits
+func (id UUIDv7) valid() bool {
+	return id[6]>>4 == 7 && id[8]>>6 == 2
+}
@@ -0,0 +1,154 @@
+package str_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/semirm-dev/godev/str"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+
+	id, err := str.NewUUIDv7()
+	assert.NoError(t, err)
+
+	assert.Len(t, id.String(), 36)
+	assert.Equal(t, byte('7'), id.String()[14])
+	assert.Contains(t, "89ab", id.String()[19:20])
+	assert.False(t, id.IsZero())
+	assert.False(t, id.Time().Before(before))
+	assert.False(t, id.Time().After(time.Now()))
+
+	parsed, err := str.ParseUUIDv7(id.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	// compatible with gocql.UUID
+	cqlUUID, err := gocql.ParseUUID(id.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, id.Bytes(), cqlUUID.Bytes())
+}
+
+// RFC 9562, appendix A.6
+func TestUUIDv7Known(t *testing.T) {
+	id, err := str.ParseUUIDv7("017F22E2-79B0-7CC3-98C4-DC0C0C07398F")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", id.String())
+	assert.Equal(t, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC), id.Time().UTC())
+}
+
+func TestUUIDv7Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398",
+		"017f22e279b07cc398c4dc0c0c07398f",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398g",
+		// version 4
+		"6ba7b810-9dad-41d1-80b4-00c04fd430c8",
+		// variant
+		"017f22e2-79b0-7cc3-18c4-dc0c0c07398f",
+	}
+
+	for _, s := range invalid {
+		_, err := str.ParseUUIDv7(s)
+		assert.Equal(t, str.ErrInvalidUUID, err, s)
+	}
+
+	_, err := str.ParseUUIDv7(str.UUID())
+	assert.Equal(t, str.ErrInvalidUUID, err)
+}
+
+func TestUUIDv7Monotonic(t *testing.T) {
+	prev, err := str.NewUUIDv7()
+	assert.NoError(t, err)
+
+	for i := 0; i < 10000; i++ {
+		id, err := str.NewUUIDv7()
+		assert.NoError(t, err)
+
+		if !assert.True(t, id.String() > prev.String(), "%s <= %s", id, prev) {
+			return
+		}
+
+		prev = id
+	}
+}
+
+func TestUUIDv7Marshaling(t *testing.T) {
+	id, err := str.NewUUIDv7()
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"`+id.String()+`"`, string(data))
+
+	var decoded str.UUIDv7
+
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, id, decoded)
+
+	binary, err := id.MarshalBinary()
+	assert.NoError(t, err)
+
+	var fromBinary str.UUIDv7
+
+	assert.NoError(t, fromBinary.UnmarshalBinary(binary))
+	assert.Equal(t, id, fromBinary)
+	assert.Equal(t, str.ErrInvalidUUID, fromBinary.UnmarshalBinary(make([]byte, 16)))
+}
+
+func TestUUIDv7SQL(t *testing.T) {
+	id, err := str.NewUUIDv7()
+	assert.NoError(t, err)
+
+	value, err := id.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, id.String(), value)
+
+	for _, src := range []interface{}{id.String(), []byte(strings.ToUpper(id.String())), id.Bytes()} {
+		var scanned str.UUIDv7
+
+		assert.NoError(t, scanned.Scan(src))
+		assert.Equal(t, id, scanned)
+	}
+}
+
+func TestUUIDv7CQL(t *testing.T) {
+	id, err := str.NewUUIDv7()
+	assert.NoError(t, err)
+
+	for _, typ := range []gocql.Type{gocql.TypeUUID, gocql.TypeBlob, gocql.TypeText} {
+		info := gocql.NewNativeType(4, typ, "")
+
+		data, err := gocql.Marshal(info, id)
+		assert.NoError(t, err)
+
+		var unmarshaled str.UUIDv7
+
+		assert.NoError(t, gocql.Unmarshal(info, data, &unmarshaled))
+		assert.Equal(t, id, unmarshaled)
+	}
+
+	// uuid column read into gocql.UUID
+	info := gocql.NewNativeType(4, gocql.TypeUUID, "")
+
+	data, err := gocql.Marshal(info, id)
+	assert.NoError(t, err)
+
+	var cqlUUID gocql.UUID
+
+	assert.NoError(t, gocql.Unmarshal(info, data, &cqlUUID))
+	assert.Equal(t, id.String(), cqlUUID.String())
+}